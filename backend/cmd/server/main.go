@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -13,25 +15,43 @@ import (
 )
 
 func main() {
-	templateRoot := os.Getenv("TEMPLATE_ROOT")
-	if templateRoot == "" {
-		templateRoot = "../templates"
-	}
-
-	registry, err := generator.NewTemplateRegistry(templateRoot)
+	// TEMPLATE_ROOT is optional: templates are embedded, and files found under
+	// it override the embedded copies.
+	registry, err := generator.NewTemplateRegistry(os.Getenv("TEMPLATE_ROOT"))
 	if err != nil {
 		log.Fatalf("failed to initialize template registry: %v", err)
 	}
+	if os.Getenv("TEMPLATE_WATCH") == "1" {
+		go registry.Watch(context.Background(), time.Second, log.Printf)
+	}
 
 	eng := generator.NewEngine(registry)
+	// Each sub-folder of TEMPLATE_PACKS_DIR becomes a pack that requests can
+	// select with template_pack.name.
+	if dir := os.Getenv("TEMPLATE_PACKS_DIR"); dir != "" {
+		names, err := eng.LoadTemplatePacks(dir)
+		if err != nil {
+			log.Fatalf("failed to load template packs: %v", err)
+		}
+		log.Printf("template packs registered: %v", names)
+	}
 	handler := api.NewHandler(eng)
 
-	app := fiber.New(fiber.Config{AppName: "StackSprint Generator API"})
+	// Diff, upgrade and template pack requests carry whole projects or
+	// archives, so allow more than fiber's 4 MB default.
+	app := fiber.New(fiber.Config{AppName: "StackSprint Generator API", BodyLimit: 64 << 20})
 	app.Use(logger.New())
 	app.Use(cors.New())
 
 	app.Get("/health", handler.Health)
+	app.Get("/capabilities", handler.Capabilities)
+	app.Get("/schema/generate-request.json", handler.GenerateRequestSchema)
+	app.Get("/template-packs", handler.TemplatePacks)
 	app.Post("/generate", handler.Generate)
+	app.Post("/generate/archive", handler.GenerateArchive)
+	app.Post("/generate/files", handler.GenerateFiles)
+	app.Post("/generate/diff", handler.GenerateDiff)
+	app.Post("/upgrade", handler.Upgrade)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"stacksprint/backend/internal/generator"
+)
+
+const usageText = `stacksprint generates backend project scaffolds without the HTTP API.
+
+Usage:
+  stacksprint new    -f stack.yaml [-o dir] [-force]     write the project to disk
+  stacksprint script -f stack.yaml [-shell bash|powershell] [-o file]
+  stacksprint plan   -f stack.yaml [-json]               list paths, warnings and decisions
+
+Common flags:
+  -f path          request file (.yaml, .yml or .json; "-" reads stdin)
+  -templates dir   template overlay; files here shadow the embedded templates
+                   (defaults to $TEMPLATE_ROOT)
+  -packs dir       folder of template packs selectable with template_pack.name
+                   (defaults to $TEMPLATE_PACKS_DIR)
+  -strict          reject unknown request fields
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usageText)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "new":
+		err = runNew(os.Args[2:])
+	case "script":
+		err = runScript(os.Args[2:])
+	case "plan":
+		err = runPlan(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usageText)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usageText)
+		os.Exit(2)
+	}
+	if err != nil {
+		printError(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+type commonFlags struct {
+	file      string
+	templates string
+	packs     string
+	strict    bool
+}
+
+func (c *commonFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.file, "f", "", "request file (.yaml, .yml or .json; \"-\" reads stdin)")
+	fs.StringVar(&c.templates, "templates", os.Getenv("TEMPLATE_ROOT"), "template overlay directory")
+	fs.StringVar(&c.packs, "packs", os.Getenv("TEMPLATE_PACKS_DIR"), "template packs directory")
+	fs.BoolVar(&c.strict, "strict", false, "reject unknown request fields")
+}
+
+func (c *commonFlags) build() (generator.Project, error) {
+	if c.file == "" {
+		return generator.Project{}, errors.New("-f is required")
+	}
+	req, err := loadRequest(c.file, c.strict)
+	if err != nil {
+		return generator.Project{}, err
+	}
+
+	registry, err := generator.NewTemplateRegistry(c.templates)
+	if err != nil {
+		return generator.Project{}, err
+	}
+	engine := generator.NewEngine(registry)
+	if c.packs != "" {
+		if _, err := engine.LoadTemplatePacks(c.packs); err != nil {
+			return generator.Project{}, err
+		}
+	}
+	return engine.Build(context.Background(), req)
+}
+
+func runNew(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	out := fs.String("o", "", "output directory (new mode: parent of root.name; existing mode: overrides root.path)")
+	force := fs.Bool("force", false, "write into a non-empty directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	project, err := common.build()
+	if err != nil {
+		return err
+	}
+	req := project.Request
+
+	dest := req.Root.Path
+	if req.Root.Mode == "new" {
+		parent := *out
+		if parent == "" {
+			parent = "."
+		}
+		dest = filepath.Join(parent, filepath.FromSlash(req.Root.Name))
+	} else if *out != "" {
+		dest = *out
+	}
+
+	if req.Root.Mode == "new" && !*force {
+		if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 {
+			return fmt.Errorf("%s is not empty (use -force to write anyway)", dest)
+		}
+	}
+	if err := generator.WriteTree(dest, project.Tree, req.Root.ConflictPolicy()); err != nil {
+		return err
+	}
+	if req.Root.Mode == "new" && req.Root.GitInit {
+		if err := gitInit(dest); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: git init skipped: %v\n", err)
+		}
+	}
+
+	for _, w := range project.Warnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	fmt.Printf("Generated %d files in %s\n", len(project.Tree.Files), dest)
+	return nil
+}
+
+func runScript(args []string) error {
+	fs := flag.NewFlagSet("script", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	shell := fs.String("shell", "bash", "script flavour: bash or powershell")
+	out := fs.String("o", "", "write the script to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	project, err := common.build()
+	if err != nil {
+		return err
+	}
+	resp, err := generator.BuildScripts(project.Request, project.Tree, project.RuleWarnings)
+	if err != nil {
+		return err
+	}
+
+	var script string
+	mode := os.FileMode(0o644)
+	switch strings.ToLower(*shell) {
+	case "bash", "sh":
+		script = resp.BashScript
+		mode = 0o755
+	case "powershell", "pwsh", "ps1":
+		script = resp.PowerShellScript
+	default:
+		return fmt.Errorf("unknown shell %q (use bash or powershell)", *shell)
+	}
+
+	if *out == "" {
+		_, err := fmt.Print(script)
+		return err
+	}
+	return os.WriteFile(*out, []byte(script), mode)
+}
+
+func runPlan(args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var common commonFlags
+	common.register(fs)
+	asJSON := fs.Bool("json", false, "print the plan as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	project, err := common.build()
+	if err != nil {
+		return err
+	}
+	resp, err := generator.BuildScripts(project.Request, project.Tree, project.RuleWarnings)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"file_paths": resp.FilePaths,
+			"warnings":   resp.Warnings,
+			"decisions":  resp.Decisions,
+		})
+	}
+
+	fmt.Println("Files:")
+	for _, p := range resp.FilePaths {
+		fmt.Printf("  %s\n", p)
+	}
+	if len(resp.Warnings) > 0 {
+		fmt.Println("\nWarnings:")
+		for _, w := range resp.Warnings {
+			fmt.Printf("  - %s\n", w)
+		}
+	}
+	fmt.Println("\nDecisions:")
+	for _, d := range resp.Decisions {
+		fmt.Printf("  [%s] %s\n", d.Code, d.Message)
+	}
+	return nil
+}
+
+// loadRequest reads a YAML or JSON request. YAML is converted to JSON first
+// so both formats go through the same struct tags and strict checks.
+func loadRequest(path string, strict bool) (generator.GenerateRequest, error) {
+	var (
+		body []byte
+		err  error
+	)
+	if path == "-" {
+		body, err = io.ReadAll(os.Stdin)
+	} else {
+		body, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return generator.GenerateRequest{}, fmt.Errorf("failed to read request: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return generator.DecodeGenerateRequest(body, strict)
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return generator.GenerateRequest{}, fmt.Errorf("failed to parse YAML request: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	asJSON, err := json.Marshal(doc)
+	if err != nil {
+		return generator.GenerateRequest{}, fmt.Errorf("failed to convert YAML request: %w", err)
+	}
+	return generator.DecodeGenerateRequest(asJSON, strict)
+}
+
+func gitInit(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return nil
+	}
+	git, err := exec.LookPath("git")
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(git, "init", "--quiet")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func printError(w io.Writer, err error) {
+	var verr *generator.ValidationError
+	if errors.As(err, &verr) {
+		fmt.Fprintln(w, "error: invalid request")
+		for _, fe := range verr.Errors {
+			fmt.Fprintf(w, "  %s: %s", fe.Path, fe.Message)
+			if fe.Hint != "" {
+				fmt.Fprintf(w, " (%s)", fe.Hint)
+			}
+			fmt.Fprintln(w)
+		}
+		return
+	}
+	var rerr *generator.RuleError
+	if errors.As(err, &rerr) {
+		fmt.Fprintf(w, "error: %s [%s]\n", rerr.Message, rerr.Code)
+		return
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	fmt.Fprintf(w, "error: %v\n", err)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stacksprint/backend/internal/generator"
+)
+
+func TestLoadRequestYAMLAndJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "stack.yaml")
+	jsonPath := filepath.Join(dir, "stack.json")
+	if err := os.WriteFile(yamlPath, []byte("language: go\nframework: gin\nfile_toggles:\n  env: false\nservices:\n  - name: users\n    port: 8081\n"), 0o644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	if err := os.WriteFile(jsonPath, []byte(`{"language":"go","framework":"gin","file_toggles":{"env":false},"services":[{"name":"users","port":8081}]}`), 0o644); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+
+	for _, p := range []string{yamlPath, jsonPath} {
+		req, err := loadRequest(p, true)
+		if err != nil {
+			t.Fatalf("load %s: %v", p, err)
+		}
+		if req.Language != "go" || req.Framework != "gin" {
+			t.Fatalf("unexpected stack from %s: %+v", p, req)
+		}
+		if req.FileToggles.Env == nil || *req.FileToggles.Env {
+			t.Fatalf("expected env toggle false from %s", p)
+		}
+		if len(req.Services) != 1 || req.Services[0].Port != 8081 {
+			t.Fatalf("unexpected services from %s: %+v", p, req.Services)
+		}
+	}
+}
+
+func TestLoadRequestStrictYAML(t *testing.T) {
+	t.Parallel()
+
+	p := filepath.Join(t.TempDir(), "stack.yml")
+	if err := os.WriteFile(p, []byte("language: go\nfile_toggle:\n  env: false\n"), 0o644); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	_, err := loadRequest(p, true)
+	var verr *generator.ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/file_toggle" {
+		t.Fatalf("expected unknown field error for /file_toggle, got %v", err)
+	}
+}
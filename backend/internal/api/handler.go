@@ -1,6 +1,9 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/gofiber/fiber/v2"
 
 	"stacksprint/backend/internal/generator"
@@ -18,16 +21,152 @@ func (h *Handler) Health(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ok"})
 }
 
+func (h *Handler) Capabilities(c *fiber.Ctx) error {
+	return c.JSON(generator.DescribeCapabilities())
+}
+
+func (h *Handler) TemplatePacks(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"packs": h.engine.TemplatePacks()})
+}
+
+func (h *Handler) GenerateRequestSchema(c *fiber.Ctx) error {
+	return c.JSON(generator.GenerateRequestSchema(), "application/schema+json")
+}
+
 func (h *Handler) Generate(c *fiber.Ctx) error {
-	var req generator.GenerateRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid JSON body", "detail": err.Error()})
+	req, err := parseGenerateRequest(c)
+	if err != nil {
+		return generationError(c, err)
 	}
 
 	result, err := h.engine.Generate(c.Context(), req)
+	if err != nil {
+		return generationError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *Handler) GenerateFiles(c *fiber.Ctx) error {
+	req, err := parseGenerateRequest(c)
+	if err != nil {
+		return generationError(c, err)
+	}
+
+	project, err := h.engine.Build(c.Context(), req)
+	if err != nil {
+		return generationError(c, err)
+	}
+
+	filters := make([]string, 0)
+	for _, v := range c.Context().QueryArgs().PeekMulti("path") {
+		filters = append(filters, string(v))
+	}
+	withContent := c.QueryBool("content", true)
+
+	return c.JSON(generator.FilesResponse{
+		Files:    generator.CollectFiles(project.Tree, filters, withContent),
+		Warnings: project.Warnings(),
+	})
+}
+
+func (h *Handler) GenerateArchive(c *fiber.Ctx) error {
+	format, err := generator.NormalizeArchiveFormat(c.Query("format"))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	req, err := parseGenerateRequest(c)
+	if err != nil {
+		return generationError(c, err)
+	}
+
+	project, err := h.engine.Build(c.Context(), req)
+	if err != nil {
+		return generationError(c, err)
+	}
+
+	name := generator.ArchiveRoot(project.Request)
+	if name == "" {
+		name = "stacksprint-generated"
+	}
+	contentType := "application/zip"
+	if format == generator.ArchiveTarGz {
+		contentType = "application/gzip"
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name+"."+format))
+
+	if err := generator.WriteArchive(c.Response().BodyWriter(), format, project.Request, project.Tree); err != nil {
+		c.Response().ResetBody()
+		c.Response().Header.Del(fiber.HeaderContentDisposition)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to build archive", "detail": err.Error()})
+	}
+	return nil
+}
+
+func (h *Handler) GenerateDiff(c *fiber.Ctx) error {
+	var req generator.DiffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return generationError(c, bodyError{err: err})
+	}
+
+	result, err := h.engine.Diff(c.Context(), req)
+	if err != nil {
+		return generationError(c, err)
+	}
 	return c.JSON(result)
 }
+
+func (h *Handler) Upgrade(c *fiber.Ctx) error {
+	var req generator.UpgradeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return generationError(c, bodyError{err: err})
+	}
+
+	result, err := h.engine.Upgrade(c.Context(), req)
+	if err != nil {
+		return generationError(c, err)
+	}
+	return c.JSON(result)
+}
+
+// bodyError wraps a body that could not be decoded at all.
+type bodyError struct {
+	err error
+}
+
+func (e bodyError) Error() string {
+	return e.err.Error()
+}
+
+// parseGenerateRequest decodes the body. With ?strict=true unknown fields are
+// rejected instead of silently ignored.
+func parseGenerateRequest(c *fiber.Ctx) (generator.GenerateRequest, error) {
+	if c.QueryBool("strict", false) {
+		return generator.DecodeGenerateRequest(c.Body(), true)
+	}
+	var req generator.GenerateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, bodyError{err: err}
+	}
+	return req, nil
+}
+
+// generationError maps engine failures onto HTTP statuses: request validation
+// problems are 400, rule-engine incompatibilities 422, anything else 500.
+func generationError(c *fiber.Ctx, err error) error {
+	var berr bodyError
+	if errors.As(err, &berr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid JSON body", "detail": berr.Error()})
+	}
+	var verr *generator.ValidationError
+	if errors.As(err, &verr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error(), "errors": verr.Errors})
+	}
+	var rerr *generator.RuleError
+	if errors.As(err, &rerr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": rerr.Message, "code": rerr.Code, "paths": rerr.Paths})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
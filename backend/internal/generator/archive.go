@@ -0,0 +1,254 @@
+package generator
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	ArchiveZip   = "zip"
+	ArchiveTarGz = "tar.gz"
+)
+
+// NormalizeArchiveFormat maps user supplied format names onto the supported
+// archive formats.
+func NormalizeArchiveFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "zip":
+		return ArchiveZip, nil
+	case "tar.gz", "tgz", "targz":
+		return ArchiveTarGz, nil
+	default:
+		return "", fmt.Errorf("archive format %q is not supported (use zip or tar.gz)", format)
+	}
+}
+
+// ArchiveRoot is the top-level folder used inside archives. Existing-mode
+// projects are extracted in place, so they have no wrapping folder.
+func ArchiveRoot(req GenerateRequest) string {
+	if strings.ToLower(req.Root.Mode) == "existing" {
+		return ""
+	}
+	name := path.Base(strings.TrimSpace(req.Root.Name))
+	if name == "." || name == "/" || name == "" {
+		return "stacksprint-generated"
+	}
+	return name
+}
+
+func WriteArchive(w io.Writer, format string, req GenerateRequest, tree FileTree) error {
+	format, err := NormalizeArchiveFormat(format)
+	if err != nil {
+		return err
+	}
+
+	tree = cloneTree(tree)
+	ensureGitKeepFiles(&tree)
+	root := ArchiveRoot(req)
+	modTime := time.Now().UTC()
+
+	if format == ArchiveTarGz {
+		return writeTarGz(w, root, tree, modTime)
+	}
+	return writeZip(w, root, tree, modTime)
+}
+
+func writeZip(w io.Writer, root string, tree FileTree, modTime time.Time) error {
+	zw := zip.NewWriter(w)
+	for _, d := range archiveDirs(root, tree) {
+		hdr := &zip.FileHeader{Name: d + "/", Method: zip.Store, Modified: modTime}
+		hdr.SetMode(fs.ModeDir | 0o755)
+		if _, err := zw.CreateHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write zip directory %s: %w", d, err)
+		}
+	}
+	for _, f := range fileNamesSorted(tree.Files) {
+		hdr := &zip.FileHeader{Name: archivePath(root, f), Method: zip.Deflate, Modified: modTime}
+		hdr.SetMode(archiveFileMode(f))
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("failed to write zip entry %s: %w", f, err)
+		}
+		if _, err := io.WriteString(fw, tree.Files[f]); err != nil {
+			return fmt.Errorf("failed to write zip entry %s: %w", f, err)
+		}
+	}
+	return zw.Close()
+}
+
+func writeTarGz(w io.Writer, root string, tree FileTree, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, d := range archiveDirs(root, tree) {
+		hdr := &tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     d + "/",
+			Mode:     0o755,
+			ModTime:  modTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write tar directory %s: %w", d, err)
+		}
+	}
+	for _, f := range fileNamesSorted(tree.Files) {
+		content := tree.Files[f]
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     archivePath(root, f),
+			Mode:     int64(archiveFileMode(f).Perm()),
+			Size:     int64(len(content)),
+			ModTime:  modTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write tar entry %s: %w", f, err)
+		}
+		if _, err := io.WriteString(tw, content); err != nil {
+			return fmt.Errorf("failed to write tar entry %s: %w", f, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// archiveDirs returns every directory entry, including parents that only
+// exist implicitly through file paths, in creation order.
+func archiveDirs(root string, tree FileTree) []string {
+	all := map[string]struct{}{}
+	addParents := func(p string) {
+		for p != "." && p != "/" && p != "" {
+			all[p] = struct{}{}
+			p = path.Dir(p)
+		}
+	}
+	for d := range tree.Dirs {
+		addParents(d)
+	}
+	for f := range tree.Files {
+		addParents(path.Dir(f))
+	}
+
+	out := make([]string, 0, len(all)+1)
+	if root != "" {
+		out = append(out, root)
+	}
+	for _, d := range dirsSorted(all) {
+		out = append(out, archivePath(root, d))
+	}
+	return out
+}
+
+func archivePath(root, p string) string {
+	if root == "" {
+		return p
+	}
+	return root + "/" + p
+}
+
+func archiveFileMode(p string) fs.FileMode {
+	base := path.Base(p)
+	if strings.HasSuffix(base, ".sh") || base == "manage.py" {
+		return 0o755
+	}
+	return 0o644
+}
+
+func cloneTree(tree FileTree) FileTree {
+	out := FileTree{
+		Files: make(map[string]string, len(tree.Files)),
+		Dirs:  make(map[string]struct{}, len(tree.Dirs)),
+	}
+	for k, v := range tree.Files {
+		out.Files[k] = v
+	}
+	for k := range tree.Dirs {
+		out.Dirs[k] = struct{}{}
+	}
+	return out
+}
+
+// ReadArchive unpacks a zip or tar.gz (detected from its magic bytes) into a
+// path -> content map. Entries that keep rejects are dropped, and the kept
+// files may not exceed maxBytes in total.
+func ReadArchive(raw []byte, maxBytes int64, keep func(name string) bool) (map[string]string, error) {
+	files := map[string]string{}
+	var total int64
+	add := func(name string, size int64, r io.Reader) error {
+		clean := path.Clean(strings.TrimPrefix(name, "./"))
+		if !fs.ValidPath(clean) {
+			return fmt.Errorf("entry %q escapes the archive root", name)
+		}
+		if keep != nil && !keep(clean) {
+			return nil
+		}
+		total += size
+		if total > maxBytes {
+			return fmt.Errorf("archive content is larger than %d bytes", maxBytes)
+		}
+		body, err := io.ReadAll(io.LimitReader(r, size))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", clean, err)
+		}
+		files[clean] = string(body)
+		return nil
+	}
+
+	switch {
+	case bytes.HasPrefix(raw, []byte("PK\x03\x04")):
+		zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+		if err != nil {
+			return nil, fmt.Errorf("corrupt zip archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+			}
+			err = add(f.Name, int64(f.UncompressedSize64), rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	case bytes.HasPrefix(raw, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("corrupt gzip stream: %w", err)
+		}
+		defer gz.Close()
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("corrupt tar stream: %w", err)
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := add(hdr.Name, hdr.Size, tr); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, errors.New("archive must be a zip or tar.gz")
+	}
+	return files, nil
+}
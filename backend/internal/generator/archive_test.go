@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestWriteArchiveZip(t *testing.T) {
+	t.Parallel()
+
+	req, tree := archiveTestProject(t)
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, "zip", req, tree); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	if f, ok := entries["archive-app/"]; !ok || !f.Mode().IsDir() {
+		t.Fatalf("expected root directory entry, got %v", ok)
+	}
+	if f, ok := entries["archive-app/app/"]; !ok || !f.Mode().IsDir() {
+		t.Fatalf("expected app/ directory entry")
+	}
+	if _, ok := entries["archive-app/uploads/.gitkeep"]; !ok {
+		t.Fatalf("expected .gitkeep for empty custom folder")
+	}
+	main, ok := entries["archive-app/app/main.py"]
+	if !ok {
+		t.Fatalf("expected app/main.py entry")
+	}
+	if main.Mode().Perm() != 0o644 {
+		t.Fatalf("expected 0644 for app/main.py, got %v", main.Mode().Perm())
+	}
+	rc, err := main.Open()
+	if err != nil {
+		t.Fatalf("open entry: %v", err)
+	}
+	defer rc.Close()
+	body, _ := io.ReadAll(rc)
+	if string(body) != tree.Files["app/main.py"] {
+		t.Fatalf("zip entry content mismatch")
+	}
+}
+
+func TestWriteArchiveTarGz(t *testing.T) {
+	t.Parallel()
+
+	req, tree := archiveTestProject(t)
+	tree.Files["scripts/bootstrap.sh"] = "#!/usr/bin/env bash\n"
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, "tar.gz", req, tree); err != nil {
+		t.Fatalf("write tar.gz: %v", err)
+	}
+
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("open gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	headers := map[string]*tar.Header{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		headers[hdr.Name] = hdr
+	}
+
+	if hdr, ok := headers["archive-app/scripts/"]; !ok || hdr.Typeflag != tar.TypeDir {
+		t.Fatalf("expected implicit scripts/ directory entry")
+	}
+	if hdr, ok := headers["archive-app/scripts/bootstrap.sh"]; !ok || hdr.Mode != 0o755 {
+		t.Fatalf("expected executable mode for shell script")
+	}
+	if _, ok := headers["archive-app/uploads/.gitkeep"]; !ok {
+		t.Fatalf("expected .gitkeep for empty custom folder")
+	}
+}
+
+func TestWriteArchiveRejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	if err := WriteArchive(io.Discard, "rar", GenerateRequest{}, FileTree{}); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
+
+func archiveTestProject(t *testing.T) (GenerateRequest, FileTree) {
+	t.Helper()
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{
+		Language:     "python",
+		Framework:    "fastapi",
+		Architecture: "mvp",
+		Root:         RootOptions{Mode: "new", Name: "archive-app"},
+		Custom:       CustomOptions{AddFolders: []string{"uploads"}},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	return project.Request, project.Tree
+}
@@ -231,10 +231,15 @@ const (
 
 func Parse(r *http.Request) (limit int, offset int) {
 	q := r.URL.Query()
+	return FromQuery(q.Get("limit"), q.Get("offset"))
+}
 
+// FromQuery applies the defaults and bounds to raw limit and offset values,
+// for routers that do not expose an *http.Request.
+func FromQuery(rawLimit, rawOffset string) (limit int, offset int) {
 	limit = DefaultLimit
-	if raw := q.Get("limit"); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+	if rawLimit != "" {
+		if parsed, err := strconv.Atoi(rawLimit); err == nil && parsed > 0 {
 			limit = parsed
 		}
 	}
@@ -243,8 +248,8 @@ func Parse(r *http.Request) (limit int, offset int) {
 	}
 
 	offset = 0
-	if raw := q.Get("offset"); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 {
+	if rawOffset != "" {
+		if parsed, err := strconv.Atoi(rawOffset); err == nil && parsed >= 0 {
 			offset = parsed
 		}
 	}
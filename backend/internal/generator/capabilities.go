@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+)
+
+type Capabilities struct {
+	Languages             []LanguageCapability      `json:"languages"`
+	Architectures         []string                  `json:"architectures"`
+	Databases             []DatabaseCapability      `json:"databases"`
+	ServiceCommunications []string                  `json:"service_communications"`
+	RootModes             []string                  `json:"root_modes"`
+	ConflictPolicies      []string                  `json:"conflict_policies"`
+	Microservices         ServiceLimits             `json:"microservices"`
+	Infra                 []OptionCapability        `json:"infra"`
+	Features              []OptionCapability        `json:"features"`
+	FileToggles           []OptionCapability        `json:"file_toggles"`
+	Incompatibilities     []Incompatibility         `json:"incompatibilities"`
+	MigrationTools        []MigrationToolCapability `json:"migration_tools"`
+}
+
+type LanguageCapability struct {
+	Name       string   `json:"name"`
+	Frameworks []string `json:"frameworks"`
+}
+
+type DatabaseCapability struct {
+	Name        string `json:"name"`
+	SupportsORM bool   `json:"supports_orm"`
+}
+
+type ServiceLimits struct {
+	MinServices int `json:"min_services"`
+	MaxServices int `json:"max_services"`
+}
+
+type OptionCapability struct {
+	Key     string `json:"key"`
+	Default bool   `json:"default"`
+}
+
+// DescribeCapabilities reports the option tables used by Validate and
+// ApplyRuleEngine so clients do not have to duplicate them.
+func DescribeCapabilities() Capabilities {
+	languages := make([]LanguageCapability, 0, len(languageOptions))
+	for _, lang := range languageOptions {
+		languages = append(languages, LanguageCapability{Name: lang, Frameworks: frameworksFor(lang)})
+	}
+
+	databases := make([]DatabaseCapability, 0, len(databaseOptions))
+	for _, db := range databaseOptions {
+		databases = append(databases, DatabaseCapability{Name: db, SupportsORM: supportsSQLORM(db)})
+	}
+
+	return Capabilities{
+		Languages:             languages,
+		Architectures:         append([]string(nil), architectureOptions...),
+		Databases:             databases,
+		ServiceCommunications: append([]string(nil), communicationOptions...),
+		RootModes:             append([]string(nil), rootModeOptions...),
+		ConflictPolicies:      append([]string(nil), conflictOptions...),
+		Microservices:         ServiceLimits{MinServices: minMicroservices, MaxServices: maxMicroservices},
+		Infra:                 optionCapabilities(InfraOptions{}),
+		Features:              optionCapabilities(FeatureOptions{}),
+		FileToggles:           optionCapabilities(FileToggleOptions{}),
+		Incompatibilities:     append([]Incompatibility(nil), stackIncompatibilities...),
+		MigrationTools:        append([]MigrationToolCapability(nil), migrationToolStacks...),
+	}
+}
+
+// optionCapabilities lists the JSON keys of a flag struct. Plain bools default
+// to false; *bool toggles are enabled unless explicitly turned off.
+func optionCapabilities(v any) []OptionCapability {
+	t := reflect.TypeOf(v)
+	out := make([]OptionCapability, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		out = append(out, OptionCapability{Key: key, Default: field.Type.Kind() == reflect.Pointer})
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDescribeCapabilitiesMatchesValidator(t *testing.T) {
+	t.Parallel()
+
+	caps := DescribeCapabilities()
+	for _, lang := range caps.Languages {
+		for _, fw := range lang.Frameworks {
+			for _, arch := range caps.Architectures {
+				req := GenerateRequest{
+					Language:     lang.Name,
+					Framework:    fw,
+					Architecture: arch,
+					Database:     "none",
+					Root:         RootOptions{Mode: "new", Name: "caps"},
+				}
+				if arch == "microservices" {
+					req.Services = []ServiceConfig{{Name: "a", Port: 1}, {Name: "b", Port: 2}}
+				}
+				if err := Validate(req); err != nil {
+					t.Fatalf("advertised combination %s/%s/%s rejected: %v", lang.Name, fw, arch, err)
+				}
+			}
+		}
+	}
+
+	for _, db := range caps.Databases {
+		if err := Validate(GenerateRequest{Language: "go", Framework: "gin", Architecture: "mvp", Database: db.Name, Root: RootOptions{Mode: "new", Name: "caps"}}); err != nil {
+			t.Fatalf("advertised database %s rejected: %v", db.Name, err)
+		}
+	}
+
+	keys := func(opts []OptionCapability) []string {
+		out := make([]string, 0, len(opts))
+		for _, o := range opts {
+			out = append(out, o.Key)
+		}
+		return out
+	}
+	if !slices.Contains(keys(caps.Features), "jwt_auth") || !slices.Contains(keys(caps.Infra), "nats") {
+		t.Fatalf("expected feature and infra keys from struct tags, got %+v %+v", caps.Features, caps.Infra)
+	}
+	for _, toggle := range caps.FileToggles {
+		if !toggle.Default {
+			t.Fatalf("file toggle %s should default to enabled", toggle.Key)
+		}
+	}
+
+	if len(caps.Incompatibilities) == 0 {
+		t.Fatalf("expected rule engine incompatibilities")
+	}
+	for _, rule := range caps.Incompatibilities {
+		_, _, err := ApplyRuleEngine(GenerateRequest{Language: rule.Language, Framework: rule.Framework, Database: rule.Database})
+		if err == nil || err.Error() != rule.Message {
+			t.Fatalf("expected rule engine to reject %+v, got %v", rule, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	ConflictOverwrite = "overwrite"
+	ConflictSkip      = "skip"
+	ConflictBackup    = "backup"
+	ConflictFail      = "fail"
+)
+
+// ConflictPolicy is the effective on_conflict policy. New projects are
+// written into a fresh folder, so they always overwrite; existing ones
+// overwrite unless on_conflict says otherwise.
+func (r RootOptions) ConflictPolicy() string {
+	if strings.ToLower(r.Mode) != "existing" || r.OnConflict == "" {
+		return ConflictOverwrite
+	}
+	return r.OnConflict
+}
+
+// conflictCandidates are the files a policy applies to. .gitkeep placeholders
+// are never worth a backup or a failure; they are only written when missing.
+func conflictCandidates(tree FileTree) []string {
+	out := make([]string, 0, len(tree.Files))
+	for _, f := range fileNamesSorted(tree.Files) {
+		if path.Base(f) != ".gitkeep" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// atRiskPaths lists the files an existing project most likely already has:
+// everything at the project root plus dependency manifests at any depth.
+func atRiskPaths(tree FileTree) []string {
+	manifests := setOf("go.mod", "go.sum", "package.json", "requirements.txt", "Dockerfile", "Makefile", "manage.py")
+	out := make([]string, 0)
+	for _, f := range conflictCandidates(tree) {
+		if _, ok := manifests[path.Base(f)]; ok || !strings.Contains(f, "/") {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func conflictDecision(req GenerateRequest, tree FileTree) DecisionEntry {
+	policy := req.Root.ConflictPolicy()
+	var action string
+	switch policy {
+	case ConflictSkip:
+		action = "existing files are left untouched"
+	case ConflictBackup:
+		action = "existing files are copied to <file>.bak before being replaced"
+	case ConflictFail:
+		action = "the script stops before writing anything if one of them exists"
+	default:
+		action = "existing files are overwritten"
+	}
+	msg := fmt.Sprintf("on_conflict=%s: %s.", policy, action)
+	if risky := atRiskPaths(tree); len(risky) > 0 {
+		msg += fmt.Sprintf(" Paths at risk: %s.", strings.Join(risky, ", "))
+	}
+	return DecisionEntry{Code: "root.conflicts", Category: "root", Message: msg}
+}
+
+func writeBashFile(b *strings.Builder, f, content, policy string) {
+	heredoc := func() {
+		b.WriteString(fmt.Sprintf("cat > %q <<'%s'\n", f, bashHeredocDelimiter))
+		b.WriteString(content)
+		if !strings.HasSuffix(content, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString(bashHeredocDelimiter + "\n")
+	}
+	if path.Base(f) == ".gitkeep" && policy != ConflictOverwrite {
+		policy = ConflictSkip
+	}
+
+	switch policy {
+	case ConflictSkip:
+		b.WriteString(fmt.Sprintf("if [ -e %q ]; then\n  echo %q\nelse\n", f, "skipped existing "+f))
+		heredoc()
+		b.WriteString("fi\n\n")
+	case ConflictBackup:
+		b.WriteString(fmt.Sprintf("if [ -e %q ]; then cp -p %q %q; fi\n", f, f, f+".bak"))
+		heredoc()
+		b.WriteString("\n")
+	default:
+		heredoc()
+		b.WriteString("\n")
+	}
+}
+
+func writeBashConflictCheck(b *strings.Builder, tree FileTree) {
+	b.WriteString("conflicts=0\n")
+	b.WriteString("for f in")
+	for _, f := range conflictCandidates(tree) {
+		b.WriteString(fmt.Sprintf(" \\\n  %q", f))
+	}
+	b.WriteString("; do\n")
+	b.WriteString("  if [ -e \"$f\" ]; then echo \"exists: $f\" >&2; conflicts=1; fi\n")
+	b.WriteString("done\n")
+	b.WriteString("if [ \"$conflicts\" -ne 0 ]; then\n")
+	b.WriteString("  echo \"Refusing to overwrite existing files (root.on_conflict=fail).\" >&2\n")
+	b.WriteString("  exit 1\n")
+	b.WriteString("fi\n\n")
+}
+
+func writePowerShellFile(b *strings.Builder, f, content, policy string) {
+	escaped := strings.ReplaceAll(f, "'", "''")
+	setContent := fmt.Sprintf("@'\n%s\n'@ | Set-Content -NoNewline '%s'\n", trimFinalNewline(content), escaped)
+	if path.Base(f) == ".gitkeep" && policy != ConflictOverwrite {
+		policy = ConflictSkip
+	}
+
+	switch policy {
+	case ConflictSkip:
+		b.WriteString(fmt.Sprintf("if (Test-Path -LiteralPath '%s') {\n    Write-Host 'skipped existing %s'\n} else {\n", escaped, escaped))
+		b.WriteString(setContent)
+		b.WriteString("}\n\n")
+	case ConflictBackup:
+		b.WriteString(fmt.Sprintf("if (Test-Path -LiteralPath '%s') { Copy-Item -LiteralPath '%s' -Destination '%s.bak' -Force }\n", escaped, escaped, escaped))
+		b.WriteString(setContent + "\n")
+	default:
+		b.WriteString(setContent + "\n")
+	}
+}
+
+func writePowerShellConflictCheck(b *strings.Builder, tree FileTree) {
+	candidates := conflictCandidates(tree)
+	quoted := make([]string, 0, len(candidates))
+	for _, f := range candidates {
+		quoted = append(quoted, fmt.Sprintf("    '%s'", strings.ReplaceAll(f, "'", "''")))
+	}
+	b.WriteString("$Conflicts = @(@(\n")
+	b.WriteString(strings.Join(quoted, ",\n"))
+	b.WriteString("\n) | Where-Object { Test-Path -LiteralPath $_ })\n")
+	b.WriteString("if ($Conflicts.Count -gt 0) {\n")
+	b.WriteString("    throw \"Refusing to overwrite existing files (root.on_conflict=fail): $($Conflicts -join ', ')\"\n")
+	b.WriteString("}\n\n")
+}
@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func existingModeRequest(dir, policy string) GenerateRequest {
+	return GenerateRequest{
+		Language:     "python",
+		Framework:    "fastapi",
+		Architecture: "mvp",
+		Database:     "none",
+		Root:         RootOptions{Mode: "existing", Path: dir, OnConflict: policy},
+	}
+}
+
+func TestExistingModeBashScriptHonorsConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	bashPath, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not found in PATH")
+	}
+	engine := testEngine(t)
+
+	cases := []struct {
+		policy     string
+		wantErr    bool
+		wantReadme string
+		wantBackup bool
+	}{
+		{policy: ConflictOverwrite},
+		{policy: ConflictSkip, wantReadme: "ours\n"},
+		{policy: ConflictBackup, wantBackup: true},
+		{policy: ConflictFail, wantErr: true, wantReadme: "ours\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.policy, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			readme := filepath.Join(dir, "README.md")
+			if err := os.WriteFile(readme, []byte("ours\n"), 0o644); err != nil {
+				t.Fatalf("seed README: %v", err)
+			}
+
+			got, err := engine.Generate(context.Background(), existingModeRequest(dir, tc.policy))
+			if err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			scriptPath := filepath.Join(t.TempDir(), "generate.sh")
+			if err := os.WriteFile(scriptPath, []byte(got.BashScript), 0o755); err != nil {
+				t.Fatalf("write script: %v", err)
+			}
+			out, err := exec.Command(bashPath, scriptPath).CombinedOutput()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("script error = %v, want error %v\n%s", err, tc.wantErr, out)
+			}
+
+			body, _ := os.ReadFile(readme)
+			if tc.wantReadme != "" && string(body) != tc.wantReadme {
+				t.Fatalf("expected README to stay %q, got %q", tc.wantReadme, body)
+			}
+			if tc.wantReadme == "" && string(body) == "ours\n" {
+				t.Fatalf("expected README to be replaced")
+			}
+			backup, err := os.ReadFile(readme + ".bak")
+			if tc.wantBackup != (err == nil) || (tc.wantBackup && string(backup) != "ours\n") {
+				t.Fatalf("unexpected backup state: %q (%v)", backup, err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "app", "main.py")); (err == nil) == tc.wantErr {
+				t.Fatalf("app/main.py presence does not match policy %s: %v", tc.policy, err)
+			}
+		})
+	}
+}
+
+func TestWriteTreeHonorsConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	tree := FileTree{
+		Files: map[string]string{"README.md": "generated\n", "app/main.py": "print()\n"},
+		Dirs:  map[string]struct{}{".": {}, "app": {}},
+	}
+	seed := func(t *testing.T) string {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("ours\n"), 0o644); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+		return dir
+	}
+	read := func(p string) string {
+		body, _ := os.ReadFile(p)
+		return string(body)
+	}
+
+	dir := seed(t)
+	if err := WriteTree(dir, tree, ConflictSkip); err != nil || read(filepath.Join(dir, "README.md")) != "ours\n" {
+		t.Fatalf("skip should keep existing files: %v", err)
+	}
+
+	dir = seed(t)
+	if err := WriteTree(dir, tree, ConflictBackup); err != nil || read(filepath.Join(dir, "README.md.bak")) != "ours\n" || read(filepath.Join(dir, "README.md")) != "generated\n" {
+		t.Fatalf("backup should copy then replace: %v", err)
+	}
+
+	dir = seed(t)
+	err := WriteTree(dir, tree, ConflictFail)
+	if err == nil || !strings.Contains(err.Error(), "README.md") {
+		t.Fatalf("fail should name the existing file, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app", "main.py")); err == nil {
+		t.Fatalf("fail must not write anything")
+	}
+}
+
+func TestConflictPolicyDecisionAndValidation(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	got, err := engine.Generate(context.Background(), existingModeRequest("/srv/app", ""))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	var decision *DecisionEntry
+	for i := range got.Decisions {
+		if got.Decisions[i].Code == "root.conflicts" {
+			decision = &got.Decisions[i]
+		}
+	}
+	if decision == nil || !strings.Contains(decision.Message, "on_conflict=overwrite") || !strings.Contains(decision.Message, "requirements.txt") || strings.Contains(decision.Message, "app/main.py") {
+		t.Fatalf("expected the default overwrite decision listing root files at risk, got %+v", decision)
+	}
+
+	_, err = engine.Generate(context.Background(), existingModeRequest("/srv/app", "merge"))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/root/on_conflict" {
+		t.Fatalf("expected on_conflict validation error, got %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	kindString   = "string"
+	kindInt      = "int"
+	kindFloat    = "float"
+	kindBool     = "bool"
+	kindDatetime = "datetime"
+
+	defaultNow      = "now"
+	maxFieldLength  = 16383
+	maxPrecision    = 65
+	literalHint     = "Use letters, digits, spaces and _ . , : @ / + - only."
+	defaultScale    = 2
+	defaultDecimalP = 10
+)
+
+// literalRegex keeps enum values and string defaults safe to splice into SQL,
+// struct tags and Python/JS/Prisma string literals without escaping.
+var literalRegex = regexp.MustCompile(`^[\p{L}\p{N} _.,:@/+-]+$`)
+
+var decimalRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// fieldKind maps a field type onto the kinds every target renders.
+func fieldKind(fieldType string) string {
+	switch strings.ToLower(strings.TrimSpace(fieldType)) {
+	case "int", "integer":
+		return kindInt
+	case "float", "float64", "double", "decimal":
+		return kindFloat
+	case "bool", "boolean":
+		return kindBool
+	case "datetime", "timestamp", "time":
+		return kindDatetime
+	default:
+		return kindString
+	}
+}
+
+func (f DataField) column() string { return strings.ToLower(f.Name) }
+
+func (f DataField) notNull() bool { return f.Required || (f.Nullable != nil && !*f.Nullable) }
+
+// mandatory reports whether requests must carry the field: NOT NULL columns
+// without a default.
+func (f DataField) mandatory() bool { return f.notNull() && f.Default == "" }
+
+// indexed is true for a plain index; unique columns already have one.
+func (f DataField) indexed() bool { return f.Index && !f.Unique }
+
+func (f DataField) maxLength() int {
+	if f.MaxLength > 0 {
+		return f.MaxLength
+	}
+	return 255
+}
+
+func (f DataField) decimal() (int, int) {
+	p, s := defaultDecimalP, defaultScale
+	if f.Precision > 0 {
+		p, s = f.Precision, min(defaultScale, f.Precision)
+	}
+	if f.Scale != nil {
+		s = *f.Scale
+	}
+	return p, s
+}
+
+func indexName(table, column string) string { return "idx_" + table + "_" + column }
+
+func checkName(table, column string) string { return table + "_" + column + "_check" }
+
+func enumCheck(column string, values []string) string {
+	return column + " IN ('" + strings.Join(values, "', '") + "')"
+}
+
+func sqlDefault(f DataField) string {
+	switch fieldKind(f.Type) {
+	case kindString:
+		return "'" + f.Default + "'"
+	case kindBool:
+		return strings.ToUpper(f.Default)
+	case kindDatetime:
+		return "CURRENT_TIMESTAMP"
+	default:
+		return f.Default
+	}
+}
+
+// sqlColumn renders a column definition with its inline constraints; indexes
+// are emitted by the caller.
+func sqlColumn(table string, f DataField) string {
+	parts := []string{f.column(), sqlTypeFromField(f)}
+	if f.notNull() {
+		parts = append(parts, "NOT NULL")
+	}
+	if f.Default != "" {
+		parts = append(parts, "DEFAULT "+sqlDefault(f))
+	}
+	if f.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if len(f.Enum) > 0 {
+		parts = append(parts, "CONSTRAINT "+checkName(table, f.column())+" CHECK ("+enumCheck(f.column(), f.Enum)+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+func gormTag(column string, f DataField) string {
+	parts := []string{"column:" + column}
+	switch fieldKind(f.Type) {
+	case kindString:
+		if f.MaxLength > 0 {
+			parts = append(parts, fmt.Sprintf("size:%d", f.MaxLength))
+		}
+	case kindFloat:
+		if f.Precision > 0 {
+			p, s := f.decimal()
+			parts = append(parts, fmt.Sprintf("type:decimal(%d,%d)", p, s))
+		}
+	}
+	if f.notNull() {
+		parts = append(parts, "not null")
+	}
+	if f.Default != "" {
+		switch fieldKind(f.Type) {
+		case kindDatetime:
+			// Parentheses keep GORM from quoting the expression.
+			parts = append(parts, "default:(CURRENT_TIMESTAMP)")
+		case kindBool:
+			parts = append(parts, "default:"+strings.ToLower(f.Default))
+		default:
+			parts = append(parts, "default:"+f.Default)
+		}
+	}
+	if f.Unique {
+		parts = append(parts, "unique")
+	}
+	if f.indexed() {
+		parts = append(parts, "index")
+	}
+	if len(f.Enum) > 0 {
+		parts = append(parts, "check:"+enumCheck(column, f.Enum))
+	}
+	return strings.Join(parts, ";")
+}
+
+// pythonDefault renders a default as a Python literal; "now" has no literal.
+func pythonDefault(f DataField) string {
+	switch fieldKind(f.Type) {
+	case kindString:
+		return `"` + f.Default + `"`
+	case kindBool:
+		return toPascal(f.Default)
+	default:
+		return f.Default
+	}
+}
+
+func jsDefault(f DataField) string {
+	switch fieldKind(f.Type) {
+	case kindString:
+		return "'" + f.Default + "'"
+	case kindBool:
+		return strings.ToLower(f.Default)
+	default:
+		return f.Default
+	}
+}
+
+func validateModelFields(verr *ValidationError, models []DataModel) {
+	for i, m := range models {
+		for j, f := range m.Fields {
+			ptr := fmt.Sprintf("/custom/models/%d/fields/%d", i, j)
+			name := fmt.Sprintf("%s.%s", m.Name, f.Name)
+			kind := fieldKind(f.Type)
+			if f.Required && f.Nullable != nil && *f.Nullable {
+				verr.add(CodeInvalidFormat, ptr+"/nullable", fmt.Sprintf("%s cannot be both required and nullable", name), "Drop nullable, or required for an optional column.")
+			}
+			if f.MaxLength != 0 {
+				if kind != kindString {
+					verr.add(CodeInvalidFormat, ptr+"/max_length", fmt.Sprintf("max_length only applies to string fields, %s is %s", name, f.Type), "Remove max_length or change the type to string.")
+				} else if f.MaxLength < 1 || f.MaxLength > maxFieldLength {
+					verr.add(CodeOutOfRange, ptr+"/max_length", fmt.Sprintf("%s max_length must be between 1 and %d", name, maxFieldLength), "Pick a length that fits a VARCHAR column in every database.")
+				}
+			}
+			if f.Precision != 0 || f.Scale != nil {
+				p, s := f.decimal()
+				switch {
+				case kind != kindFloat:
+					verr.add(CodeInvalidFormat, ptr+"/precision", fmt.Sprintf("precision only applies to float fields, %s is %s", name, f.Type), "Remove precision and scale or change the type to float.")
+				case f.Precision == 0:
+					verr.add(CodeRequired, ptr+"/precision", fmt.Sprintf("%s sets scale without precision", name), "Set precision, the total number of digits.")
+				case p < 1 || p > maxPrecision:
+					verr.add(CodeOutOfRange, ptr+"/precision", fmt.Sprintf("%s precision must be between 1 and %d", name, maxPrecision), "MySQL caps DECIMAL precision at 65 digits.")
+				case s < 0 || s > p:
+					verr.add(CodeOutOfRange, ptr+"/scale", fmt.Sprintf("%s scale must be between 0 and its precision %d", name, p), "Scale counts the digits after the decimal point.")
+				}
+			}
+			if len(f.Enum) > 0 {
+				if kind != kindString {
+					verr.add(CodeInvalidFormat, ptr+"/enum", fmt.Sprintf("enum only applies to string fields, %s is %s", name, f.Type), "Remove enum or change the type to string.")
+				}
+				seen := map[string]struct{}{}
+				for k, v := range f.Enum {
+					vp := fmt.Sprintf("%s/enum/%d", ptr, k)
+					if !literalRegex.MatchString(v) {
+						verr.add(CodeInvalidFormat, vp, fmt.Sprintf("%s enum value %q is invalid", name, v), literalHint)
+					} else if _, ok := seen[v]; ok {
+						verr.add(CodeDuplicate, vp, fmt.Sprintf("%s enum value %q is listed twice", name, v), "Enum values must be unique.")
+					} else if len([]rune(v)) > f.maxLength() {
+						verr.add(CodeOutOfRange, vp, fmt.Sprintf("%s enum value %q is longer than %d", name, v, f.maxLength()), "Raise max_length or shorten the value.")
+					}
+					seen[v] = struct{}{}
+				}
+			}
+			if f.Default != "" {
+				if msg, hint := defaultError(f, kind); msg != "" {
+					verr.add(CodeInvalidFormat, ptr+"/default", fmt.Sprintf("%s default %q %s", name, f.Default, msg), hint)
+				}
+			}
+		}
+	}
+}
+
+func defaultError(f DataField, kind string) (string, string) {
+	const typeHint = "Use a literal of the field type, or \"now\" for datetime fields."
+	switch kind {
+	case kindInt:
+		if _, err := strconv.ParseInt(f.Default, 10, 64); err != nil {
+			return "is not an integer", typeHint
+		}
+	case kindFloat:
+		if !decimalRegex.MatchString(f.Default) {
+			return "is not a number", typeHint
+		}
+	case kindBool:
+		if !strings.EqualFold(f.Default, "true") && !strings.EqualFold(f.Default, "false") {
+			return "must be true or false", typeHint
+		}
+	case kindDatetime:
+		if f.Default != defaultNow {
+			return `must be "now"`, typeHint
+		}
+	default:
+		switch {
+		case !literalRegex.MatchString(f.Default):
+			return "has unsupported characters", literalHint
+		case len([]rune(f.Default)) > f.maxLength():
+			return fmt.Sprintf("is longer than %d", f.maxLength()), "Raise max_length or shorten the default."
+		case len(f.Enum) > 0 && !slices.Contains(f.Enum, f.Default):
+			return "is not one of the enum values", "Pick the default from enum."
+		}
+	}
+	return "", ""
+}
+
+// Field names reach SQL unquoted and become Python and JavaScript identifiers,
+// so a reserved word there breaks the generated project.
+var (
+	jsReservedWords  = []string{"arguments", "await", "break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "enum", "eval", "export", "extends", "false", "finally", "for", "function", "if", "implements", "import", "in", "instanceof", "interface", "let", "new", "null", "package", "private", "protected", "public", "return", "static", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with", "yield"}
+	sqlReservedWords = []string{"all", "and", "as", "asc", "between", "by", "case", "check", "column", "constraint", "create", "cross", "default", "delete", "desc", "distinct", "drop", "else", "end", "exists", "foreign", "from", "group", "having", "in", "index", "inner", "insert", "into", "is", "join", "key", "left", "like", "limit", "not", "null", "offset", "on", "or", "order", "primary", "references", "right", "select", "set", "table", "then", "to", "union", "unique", "update", "user", "using", "values", "when", "where", "with"}
+)
+
+// reservedName says why name cannot be used in a lang project on db, or
+// returns "" when it can. Association names are not columns, so SQL allows them.
+func reservedName(lang, db, name string, column bool) string {
+	switch {
+	case lang == "python" && slices.Contains(pythonKeywords, name):
+		return "a Python keyword"
+	case lang == "node" && slices.Contains(jsReservedWords, name):
+		return "a reserved word in JavaScript"
+	case column && isSQLDB(db) && slices.Contains(sqlReservedWords, strings.ToLower(name)):
+		return "a reserved word in SQL"
+	}
+	return ""
+}
+
+// reservedModelNames calls report for every field of m, and every association
+// named after a foreign key, e.g. from for from_id, that reservedName rejects.
+// ptr points below the model, e.g. /fields/0/name; problem leaves out the model.
+func reservedModelNames(lang, db string, m DataModel, report func(ptr, problem string)) {
+	for j, f := range m.Fields {
+		if reason := reservedName(lang, db, f.Name, true); reason != "" {
+			report(fmt.Sprintf("/fields/%d/name", j), fmt.Sprintf("%s is %s", f.Name, reason))
+		}
+	}
+	for k, rel := range m.Relations {
+		fk := strings.TrimSpace(rel.ForeignKey)
+		attr, ok := strings.CutSuffix(fk, "_id")
+		if !ok {
+			continue
+		}
+		if reason := reservedName(lang, db, attr, false); reason != "" {
+			report(fmt.Sprintf("/relations/%d/foreign_key", k), fmt.Sprintf("%s names the association %s, which is %s", fk, attr, reason))
+		}
+	}
+}
+
+// reservedHint is the hint for names that reservedModelNames reports.
+const reservedHint = "Rename it, e.g. with the model name as a prefix."
+
+// validateReservedNames rejects the declared names that reservedModelNames
+// reports; importDDL and importOpenAPI report imported ones against their source.
+func validateReservedNames(verr *ValidationError, lang, db string, models []DataModel) {
+	for i, m := range models {
+		reservedModelNames(lang, db, m, func(ptr, problem string) {
+			verr.add(CodeInvalidFormat, fmt.Sprintf("/custom/models/%d%s", i, ptr), m.Name+"."+problem, reservedHint)
+		})
+	}
+}
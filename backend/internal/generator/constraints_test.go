@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func constrainedModels() []DataModel {
+	notNull := false
+	scale := 3
+	return []DataModel{{Name: "post", Fields: []DataField{
+		{Name: "title", Type: "string", Required: true, MaxLength: 120},
+		{Name: "slug", Type: "string", Unique: true, Index: true},
+		{Name: "status", Type: "string", Enum: []string{"draft", "published"}, Default: "draft", Nullable: &notNull, Index: true},
+		{Name: "views", Type: "int", Default: "0", Nullable: &notNull},
+		{Name: "price", Type: "float", Precision: 8, Scale: &scale},
+		{Name: "published_at", Type: "datetime", Default: "now"},
+		{Name: "active", Type: "bool", Default: "true"},
+	}}}
+}
+
+func TestFieldConstraintsRender(t *testing.T) {
+	t.Parallel()
+
+	models := constrainedModels()
+	cases := []struct {
+		name string
+		got  string
+		want []string
+	}{
+		{name: "postgres", got: renderSQLTablesTemplate("postgresql", models, true), want: []string{
+			"  title VARCHAR(120) NOT NULL,\n",
+			"  slug VARCHAR(255) UNIQUE,\n",
+			"  status VARCHAR(255) NOT NULL DEFAULT 'draft' CONSTRAINT posts_status_check CHECK (status IN ('draft', 'published')),\n",
+			"  views INT NOT NULL DEFAULT 0,\n",
+			"  price DECIMAL(8,3),\n",
+			"  published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n",
+			"  active BOOLEAN DEFAULT TRUE\n);\nCREATE INDEX IF NOT EXISTS idx_posts_status ON posts (status);\n",
+		}},
+		{name: "mysql", got: renderSQLTablesTemplate("mysql", models, false), want: []string{
+			"  active BOOLEAN DEFAULT TRUE,\n  INDEX idx_posts_status (status)\n);",
+		}},
+		{name: "gorm", got: goDomainSource(t, models), want: []string{
+			`gorm:"column:title;size:120;not null"`,
+			`gorm:"column:slug;unique"`,
+			`gorm:"column:status;not null;default:draft;index;check:status IN ('draft', 'published')"`,
+			`gorm:"column:price;type:decimal(8,3)"`,
+			`gorm:"column:published_at;default:(CURRENT_TIMESTAMP)"`,
+		}},
+		{name: "prisma", got: renderPrismaSchema("postgresql", models), want: []string{
+			"  title String @db.VarChar(120)\n",
+			"  slug String? @unique\n",
+			"  /// one of: draft, published\n  status String @default(\"draft\")\n",
+			"  price Decimal? @db.Decimal(8, 3)\n",
+			"  published_at DateTime? @default(now())\n",
+			"  @@index([status], map: \"idx_posts_status\")\n  @@map(\"posts\")",
+		}},
+		{name: "sqlalchemy", got: renderSQLAlchemyModels(models), want: []string{
+			`__table_args__ = (CheckConstraint("status IN ('draft', 'published')", name="posts_status_check"),)`,
+			`title: Mapped[str] = mapped_column(String(120))`,
+			`status: Mapped[str] = mapped_column(String(255), index=True, server_default="draft")`,
+			`views: Mapped[int] = mapped_column(Integer, server_default=text("0"))`,
+			`price: Mapped[float | None] = mapped_column(Numeric(8, 3, asdecimal=False))`,
+			`published_at: Mapped[str | None] = mapped_column(DateTime, server_default=func.now())`,
+		}},
+		{name: "knex", got: knexInitMigration(models), want: []string{
+			"table.string('status', 255).notNullable().defaultTo('draft').index('idx_posts_status').checkIn(['draft', 'published'], 'posts_status_check');",
+			"table.timestamp('published_at').defaultTo(knex.fn.now());",
+		}},
+		{name: "alembic", got: alembicInitRevision(models), want: []string{
+			`sa.Column("title", sa.String(120), nullable=False),`,
+			`sa.Column("active", sa.Boolean(), server_default=sa.text("TRUE")),`,
+			`op.create_index("idx_posts_status", "posts", ["status"])`,
+		}},
+		{name: "django", got: renderDjangoModels(models), want: []string{
+			"from django.db.models.functions import Now\n",
+			"title = models.CharField(max_length=120)\n",
+			"slug = models.CharField(max_length=255, null=True, blank=True, unique=True)\n",
+			"choices=[('draft', 'draft'), ('published', 'published')]",
+			"published_at = models.DateTimeField(null=True, blank=True, db_default=Now())",
+			"models.CheckConstraint(condition=models.Q(status__in=['draft', 'published']), name='posts_status_check')",
+		}},
+		{name: "pydantic", got: renderPydanticModels(models, "int"), want: []string{
+			`title: str = Field(min_length=1, max_length=120)`,
+			`status: Literal["draft", "published"] = "draft"`,
+			`views: int = 0`,
+			`published_at: datetime | None = Field(default_factory=datetime.now)`,
+			`active: bool | None = True`,
+		}},
+		{name: "node", got: renderNodeModels(models)["post"], want: []string{
+			"constructor({ id, title, slug, status = 'draft', views = 0, price, published_at, active = true } = {})",
+			"if (input.title == null || input.title === '') errors.push('title is required');",
+			"if (input.status != null && !['draft', 'published'].includes(input.status)) errors.push('status must be one of: draft, published');",
+		}},
+	}
+	for _, tc := range cases {
+		for _, want := range tc.want {
+			if !strings.Contains(tc.got, want) {
+				t.Fatalf("%s: expected %q, got:\n%s", tc.name, want, tc.got)
+			}
+		}
+	}
+	if strings.Contains(renderSQLTablesTemplate("postgresql", models, true), "INSERT INTO posts") {
+		t.Fatalf("expected no seed row for a table with a required column")
+	}
+}
+
+func TestFieldConstraintsGoValidate(t *testing.T) {
+	t.Parallel()
+
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "clean",
+		Database:     "postgresql",
+		UseORM:       true,
+		Custom:       CustomOptions{Models: constrainedModels()},
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	domain := project.Tree.Files["internal/domain/post.go"]
+	for _, want := range []string{
+		"import (\n\t\"errors\"\n\t\"time\"\n)",
+		"PublishedAt *time.Time `json:\"published_at\" gorm:\"column:published_at;default:(CURRENT_TIMESTAMP)\"`",
+		"Status string `json:\"status\" gorm:\"column:status;not null;default:draft;index;check:status IN ('draft', 'published')\"`",
+		"\tif m.Title == \"\" {\n\t\treturn errors.New(\"title is required\")",
+		"len([]rune(m.Title)) > 120",
+		"\tcase \"\", \"draft\", \"published\":\n",
+	} {
+		if !strings.Contains(domain, want) {
+			t.Fatalf("expected domain to contain %q, got:\n%s", want, domain)
+		}
+	}
+}
+
+func TestValidateFieldConstraints(t *testing.T) {
+	t.Parallel()
+
+	nullable := true
+	negative := -1
+	cases := []struct {
+		field DataField
+		path  string
+		code  string
+	}{
+		{DataField{Name: "a", Type: "string", Required: true, Nullable: &nullable}, "/nullable", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "int", MaxLength: 10}, "/max_length", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "string", MaxLength: 70000}, "/max_length", CodeOutOfRange},
+		{DataField{Name: "a", Type: "string", Precision: 5}, "/precision", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "float", Scale: &negative}, "/precision", CodeRequired},
+		{DataField{Name: "a", Type: "float", Precision: 70}, "/precision", CodeOutOfRange},
+		{DataField{Name: "a", Type: "float", Precision: 4, Scale: &negative}, "/scale", CodeOutOfRange},
+		{DataField{Name: "a", Type: "int", Enum: []string{"x"}}, "/enum", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "string", Enum: []string{"x", "x"}}, "/enum/1", CodeDuplicate},
+		{DataField{Name: "a", Type: "string", Enum: []string{"it's"}}, "/enum/0", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "string", Enum: []string{"x"}, Default: "y"}, "/default", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "int", Default: "1.5"}, "/default", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "float", Default: "NaN"}, "/default", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "bool", Default: "yes"}, "/default", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "datetime", Default: "2024-01-01"}, "/default", CodeInvalidFormat},
+		{DataField{Name: "a", Type: "string", Default: "x'; DROP TABLE t; --"}, "/default", CodeInvalidFormat},
+	}
+	engine := testEngine(t)
+	for _, tc := range cases {
+		req := GenerateRequest{Language: "go", Framework: "gin", Architecture: "mvp", Database: "postgresql"}
+		req.Custom.Models = []DataModel{{Name: "thing", Fields: []DataField{tc.field}}}
+		_, err := engine.Build(context.Background(), req)
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected validation error for %+v, got %v", tc.field, err)
+		}
+		want := "/custom/models/0/fields/0" + tc.path
+		if verr.Errors[0].Path != want || verr.Errors[0].Code != tc.code {
+			t.Fatalf("expected %s at %s for %+v, got %+v", tc.code, want, tc.field, verr.Errors)
+		}
+	}
+}
@@ -0,0 +1,419 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	CustomReplace        = "replace"
+	CustomAppend         = "append"
+	CustomPrepend        = "prepend"
+	CustomInsertAtAnchor = "insert-at-anchor"
+	CustomJSONMerge      = "json-merge"
+	CustomYAMLMerge      = "yaml-merge"
+
+	customMarker = "--- custom code ---"
+)
+
+var customFileModes = []string{CustomReplace, CustomAppend, CustomPrepend, CustomInsertAtAnchor, CustomJSONMerge, CustomYAMLMerge}
+
+// customFileMode resolves an empty mode for a file that already exists:
+// structured files are merged, everything else gets the content appended.
+func customFileMode(f CustomFile, p string) string {
+	if f.Mode != "" {
+		return f.Mode
+	}
+	switch path.Ext(p) {
+	case ".json":
+		return CustomJSONMerge
+	case ".yaml", ".yml":
+		return CustomYAMLMerge
+	default:
+		return CustomAppend
+	}
+}
+
+func applyCustomizations(tree *FileTree, c CustomOptions) error {
+	for _, d := range c.AddFolders {
+		tree.Dirs[filepath.ToSlash(d)] = struct{}{}
+	}
+	verr := &ValidationError{}
+	for i, f := range c.AddFiles {
+		p := filepath.ToSlash(strings.TrimPrefix(f.Path, "./"))
+		existing, exists := tree.Files[p]
+		if !exists {
+			if f.Mode == CustomInsertAtAnchor {
+				verr.add(CodeInvalidPath, fmt.Sprintf("/custom/add_files/%d/path", i),
+					fmt.Sprintf("insert-at-anchor needs a generated file, but %q is not generated", p),
+					"Pick a generated file or use mode append/replace.")
+				continue
+			}
+			addFile(tree, p, f.Content)
+			continue
+		}
+		merged, err := mergeCustomFile(p, existing, f)
+		if err != nil {
+			field := "mode"
+			if f.Mode == CustomInsertAtAnchor {
+				field = "anchor"
+			}
+			verr.add(CodeInvalidFormat, fmt.Sprintf("/custom/add_files/%d/%s", i, field),
+				fmt.Sprintf("cannot merge custom file %q: %v", p, err),
+				"Check the anchor text or choose another mode such as append or replace.")
+			continue
+		}
+		tree.Files[p] = merged
+	}
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+
+	for _, d := range c.RemoveFolders {
+		d = filepath.ToSlash(d)
+		delete(tree.Dirs, d)
+		for file := range tree.Files {
+			if file == d || strings.HasPrefix(file, d+"/") {
+				delete(tree.Files, file)
+			}
+		}
+	}
+	for _, f := range c.RemoveFiles {
+		delete(tree.Files, filepath.ToSlash(f))
+	}
+	return nil
+}
+
+func mergeCustomFile(p, existing string, f CustomFile) (string, error) {
+	switch customFileMode(f, p) {
+	case CustomReplace:
+		return f.Content, nil
+	case CustomPrepend:
+		head, rest := splitFileHeader(p, existing)
+		return head + customBlock(p, existing, f.Content) + "\n" + rest, nil
+	case CustomInsertAtAnchor:
+		lines := splitLines(existing)
+		for i, line := range lines {
+			if strings.Contains(line, f.Anchor) {
+				before := strings.Join(lines[:i+1], "")
+				if !strings.HasSuffix(before, "\n") {
+					before += "\n"
+				}
+				return before + customBlock(p, existing, f.Content) + strings.Join(lines[i+1:], ""), nil
+			}
+		}
+		return "", fmt.Errorf("anchor %q not found", f.Anchor)
+	case CustomJSONMerge:
+		return mergeJSON(existing, f.Content)
+	case CustomYAMLMerge:
+		return mergeYAML(existing, f.Content)
+	default:
+		out := existing
+		if out != "" && !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		return out + "\n" + customBlock(p, existing, f.Content), nil
+	}
+}
+
+// customBlock is the injected content preceded by a marker comment in the
+// file's own syntax, ending with a newline.
+func customBlock(p, existing, content string) string {
+	if path.Ext(p) == ".go" && goPackageClause(existing) != "" {
+		content = stripGoPackageClause(content)
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if marker := commentLine(p, customMarker); marker != "" {
+		return marker + "\n" + content
+	}
+	return content
+}
+
+// commentLine renders text as a line comment for the file type, or "" when
+// the format has no comments (JSON) or is unknown.
+func commentLine(p, text string) string {
+	base := path.Base(p)
+	switch {
+	case base == "Dockerfile", base == "Makefile", strings.HasPrefix(base, ".env"), base == ".gitignore", base == ".dockerignore":
+		return "# " + text
+	}
+	switch path.Ext(p) {
+	case ".go", ".js", ".mjs", ".cjs", ".jsx", ".ts", ".tsx", ".proto", ".java", ".kt", ".rs", ".c", ".h":
+		return "// " + text
+	case ".py", ".yaml", ".yml", ".toml", ".sh", ".ini", ".cfg", ".conf", ".txt", ".mk", ".rb", ".tf":
+		return "# " + text
+	case ".sql", ".lua":
+		return "-- " + text
+	case ".md", ".html", ".xml", ".svg":
+		return "<!-- " + text + " -->"
+	case ".css", ".scss":
+		return "/* " + text + " */"
+	default:
+		return ""
+	}
+}
+
+// splitFileHeader separates the lines that must stay first (a shebang or the
+// Go package clause) from the rest of the file.
+func splitFileHeader(p, content string) (string, string) {
+	lines := splitLines(content)
+	n := 0
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
+		n = 1
+	}
+	if path.Ext(p) == ".go" {
+		for i, line := range lines {
+			if strings.HasPrefix(line, "package ") {
+				n = i + 1
+				break
+			}
+		}
+	}
+	if n == 0 {
+		return "", content
+	}
+	head := strings.Join(lines[:n], "")
+	if !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return head + "\n", strings.TrimLeft(strings.Join(lines[n:], ""), "\n")
+}
+
+func goPackageClause(content string) string {
+	for _, line := range splitLines(content) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "package ") {
+			return trimmed
+		}
+		return ""
+	}
+	return ""
+}
+
+// stripGoPackageClause drops the package clause from a snippet that is being
+// spliced into a file which already declares one.
+func stripGoPackageClause(content string) string {
+	lines := splitLines(content)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "package ") {
+			return strings.TrimLeft(strings.Join(append(lines[:i:i], lines[i+1:]...), ""), "\n")
+		}
+		break
+	}
+	return content
+}
+
+// jsonObject keeps key order so merged package.json files stay readable.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func parseOrderedJSON(s string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	v, err := decodeOrderedJSON(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+func decodeOrderedJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &jsonObject{values: map[string]any{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			val, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = val
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			val, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, val)
+		}
+		_, err := dec.Token()
+		return arr, err
+	default:
+		return tok, nil
+	}
+}
+
+// mergeJSONValues deep-merges objects; for any other pair the overlay wins.
+func mergeJSONValues(base, overlay any) any {
+	b, okB := base.(*jsonObject)
+	o, okO := overlay.(*jsonObject)
+	if !okB || !okO {
+		return overlay
+	}
+	for _, k := range o.keys {
+		if cur, ok := b.values[k]; ok {
+			b.values[k] = mergeJSONValues(cur, o.values[k])
+			continue
+		}
+		b.keys = append(b.keys, k)
+		b.values[k] = o.values[k]
+	}
+	return b
+}
+
+func writeJSON(buf *bytes.Buffer, v any, indent string) error {
+	switch val := v.(type) {
+	case *jsonObject:
+		if len(val.keys) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, k := range val.keys {
+			buf.WriteString(indent + "  ")
+			if err := writeJSON(buf, k, indent+"  "); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := writeJSON(buf, val.values[k], indent+"  "); err != nil {
+				return err
+			}
+			if i < len(val.keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+	case []any:
+		if len(val) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range val {
+			buf.WriteString(indent + "  ")
+			if err := writeJSON(buf, item, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(val)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "]")
+	default:
+		var scalar bytes.Buffer
+		enc := json.NewEncoder(&scalar)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(val); err != nil {
+			return err
+		}
+		buf.Write(bytes.TrimSuffix(scalar.Bytes(), []byte("\n")))
+	}
+	return nil
+}
+
+func mergeJSON(existing, content string) (string, error) {
+	base, err := parseOrderedJSON(existing)
+	if err != nil {
+		return "", fmt.Errorf("generated file is not valid JSON: %w", err)
+	}
+	overlay, err := parseOrderedJSON(content)
+	if err != nil {
+		return "", fmt.Errorf("content is not valid JSON: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, mergeJSONValues(base, overlay), ""); err != nil {
+		return "", err
+	}
+	buf.WriteByte('\n')
+	return buf.String(), nil
+}
+
+// mergeYAMLNodes deep-merges mappings in place; for any other pair the
+// overlay replaces the base node.
+func mergeYAMLNodes(base, overlay *yaml.Node) {
+	if base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		*base = *overlay
+		return
+	}
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, val := overlay.Content[i], overlay.Content[i+1]
+		found := false
+		for j := 0; j+1 < len(base.Content); j += 2 {
+			if base.Content[j].Value == key.Value {
+				mergeYAMLNodes(base.Content[j+1], val)
+				found = true
+				break
+			}
+		}
+		if !found {
+			base.Content = append(base.Content, key, val)
+		}
+	}
+}
+
+func mergeYAML(existing, content string) (string, error) {
+	var base, overlay yaml.Node
+	if err := yaml.Unmarshal([]byte(existing), &base); err != nil {
+		return "", fmt.Errorf("generated file is not valid YAML: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(content), &overlay); err != nil {
+		return "", fmt.Errorf("content is not valid YAML: %w", err)
+	}
+	if len(overlay.Content) == 0 {
+		return existing, nil
+	}
+	if len(base.Content) == 0 {
+		return content, nil
+	}
+	mergeYAMLNodes(base.Content[0], overlay.Content[0])
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&base); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMergeCustomFileModes(t *testing.T) {
+	t.Parallel()
+
+	goFile := "// Package main runs the app.\npackage main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n"
+	cases := []struct {
+		name     string
+		path     string
+		existing string
+		file     CustomFile
+		want     string
+	}{
+		{
+			name:     "go append drops package clause",
+			path:     "cmd/server/main.go",
+			existing: goFile,
+			file:     CustomFile{Content: "package main\n\nfunc extra() {}\n"},
+			want:     goFile + "\n// --- custom code ---\nfunc extra() {}\n",
+		},
+		{
+			name:     "go prepend lands after package clause",
+			path:     "main.go",
+			existing: goFile,
+			file:     CustomFile{Mode: CustomPrepend, Content: "import \"os\""},
+			want:     "// Package main runs the app.\npackage main\n\n// --- custom code ---\nimport \"os\"\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n",
+		},
+		{
+			name:     "python append",
+			path:     "app/main.py",
+			existing: "app = FastAPI()\n",
+			file:     CustomFile{Content: "print(app)\n"},
+			want:     "app = FastAPI()\n\n# --- custom code ---\nprint(app)\n",
+		},
+		{
+			name:     "env append",
+			path:     ".env",
+			existing: "PORT=8080",
+			file:     CustomFile{Mode: CustomAppend, Content: "FEATURE=1\n"},
+			want:     "PORT=8080\n\n# --- custom code ---\nFEATURE=1\n",
+		},
+		{
+			name:     "sql insert at anchor",
+			path:     "db/init/001_init.sql",
+			existing: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			file:     CustomFile{Mode: CustomInsertAtAnchor, Anchor: "TABLE a", Content: "CREATE INDEX a_id ON a (id);\n"},
+			want:     "CREATE TABLE a (id INT);\n-- --- custom code ---\nCREATE INDEX a_id ON a (id);\nCREATE TABLE b (id INT);\n",
+		},
+		{
+			name:     "replace",
+			path:     "README.md",
+			existing: "old\n",
+			file:     CustomFile{Mode: CustomReplace, Content: "new\n"},
+			want:     "new\n",
+		},
+		{
+			name:     "json merge keeps order",
+			path:     "package.json",
+			existing: "{\n  \"name\": \"app\",\n  \"scripts\": {\n    \"start\": \"node index.js\"\n  }\n}\n",
+			file:     CustomFile{Content: `{"scripts": {"lint": "eslint . && echo ok"}, "private": true}`},
+			want:     "{\n  \"name\": \"app\",\n  \"scripts\": {\n    \"start\": \"node index.js\",\n    \"lint\": \"eslint . && echo ok\"\n  },\n  \"private\": true\n}\n",
+		},
+		{
+			name:     "yaml merge",
+			path:     "docker-compose.yaml",
+			existing: "services:\n  app:\n    image: app\n",
+			file:     CustomFile{Content: "services:\n  app:\n    restart: always\n  redis:\n    image: redis\n"},
+			want:     "services:\n  app:\n    image: app\n    restart: always\n  redis:\n    image: redis\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := mergeCustomFile(tc.path, tc.existing, tc.file)
+			if err != nil {
+				t.Fatalf("merge: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("merge mismatch:\n got %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCustomFilesMergeIntoGeneratedProject(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	req := GenerateRequest{
+		Language:     "node",
+		Framework:    "express",
+		Architecture: "mvp",
+		Database:     "none",
+		Custom: CustomOptions{AddFiles: []CustomFile{
+			{Path: "package.json", Content: `{"dependencies": {"zod": "^3.23.0"}}`},
+		}},
+	}
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	var pkg map[string]any
+	if err := json.Unmarshal([]byte(project.Tree.Files["package.json"]), &pkg); err != nil {
+		t.Fatalf("merged package.json is not valid JSON: %v", err)
+	}
+	deps, _ := pkg["dependencies"].(map[string]any)
+	if deps["zod"] != "^3.23.0" || deps["express"] == nil {
+		t.Fatalf("expected merged dependencies, got %v", deps)
+	}
+
+	req.Custom.AddFiles = []CustomFile{{Path: "package.json", Mode: CustomInsertAtAnchor, Anchor: "no such line", Content: "x"}}
+	_, err = engine.Build(context.Background(), req)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/custom/add_files/0/anchor" {
+		t.Fatalf("expected anchor error, got %v", err)
+	}
+}
+
+func TestValidateCustomFileModes(t *testing.T) {
+	t.Parallel()
+
+	req := GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "mvp",
+		Database:     "none",
+		Custom: CustomOptions{AddFiles: []CustomFile{
+			{Path: "a.txt", Mode: "merge"},
+			{Path: "b.go", Mode: CustomInsertAtAnchor},
+			{Path: "c.json", Mode: CustomJSONMerge, Content: "{"},
+		}},
+	}
+	err := Validate(normalize(req))
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	got := map[string]string{}
+	for _, fe := range verr.Errors {
+		got[fe.Path] = fe.Code
+	}
+	want := map[string]string{
+		"/custom/add_files/0/mode":    CodeInvalidChoice,
+		"/custom/add_files/1/anchor":  CodeRequired,
+		"/custom/add_files/2/content": CodeInvalidJSON,
+	}
+	for p, code := range want {
+		if got[p] != code {
+			t.Fatalf("expected %s at %s, got %v", code, p, got)
+		}
+	}
+	if !strings.Contains(verr.Error(), "mode must be one of") {
+		t.Fatalf("unexpected message: %v", verr)
+	}
+}
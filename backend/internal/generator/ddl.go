@@ -0,0 +1,960 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ddlTextLength caps unbounded text columns; wider VARCHARs quickly overrun
+// MySQL's 64KB row limit.
+const ddlTextLength = 4000
+
+const ddlHint = "Paste CREATE TABLE statements in the Postgres or MySQL dialect, separated by ';'."
+
+// ddlToken is a lexeme of a DDL script. Kind is 'w' for words and numbers,
+// 'q' for quoted identifiers, 's' for string literals and 'p' for
+// punctuation.
+type ddlToken struct {
+	kind byte
+	text string
+	line int
+}
+
+func (t ddlToken) is(words ...string) bool {
+	return t.kind == 'w' && slices.ContainsFunc(words, func(w string) bool { return strings.EqualFold(t.text, w) })
+}
+
+func (t ddlToken) punct(p string) bool { return t.kind == 'p' && t.text == p }
+
+// ident is the lowercased name of a word or quoted identifier.
+func (t ddlToken) ident() (string, bool) {
+	if t.kind != 'w' && t.kind != 'q' {
+		return "", false
+	}
+	return strings.ToLower(t.text), true
+}
+
+type ddlColumn struct {
+	name     string
+	field    DataField
+	primary  bool
+	refTable string
+	refCol   string
+}
+
+type ddlTable struct {
+	name    string
+	columns []*ddlColumn
+	primary []string
+}
+
+func (t *ddlTable) column(name string) *ddlColumn {
+	for _, c := range t.columns {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+type ddlParser struct {
+	tables   []*ddlTable
+	enums    map[string][]string
+	warnings []string
+}
+
+func (p *ddlParser) warn(format string, args ...any) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *ddlParser) table(name string) *ddlTable {
+	for _, t := range p.tables {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// ParseDDL turns CREATE TABLE statements (Postgres or MySQL dialect) into
+// custom models. CREATE INDEX, CREATE TYPE ... AS ENUM and ALTER TABLE ... ADD
+// constraints are applied to the tables they name; other statements are
+// skipped. Column types map onto the field types of DataField, foreign keys
+// to an id column become belongs_to relations, and tables holding only two
+// foreign keys become many_to_many join tables. The warnings list what could
+// not be carried over.
+func ParseDDL(ddl string) ([]DataModel, []string, error) {
+	tokens, err := lexDDL(ddl)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &ddlParser{enums: map[string][]string{}}
+	for _, stmt := range splitDDLStatements(tokens) {
+		switch {
+		case stmt[0].is("create") && slices.ContainsFunc(stmt[1:min(len(stmt), 4)], func(t ddlToken) bool { return t.is("table") }):
+			err = p.createTable(stmt)
+		case stmt[0].is("create") && len(stmt) > 1 && stmt[1].is("type"):
+			err = p.createType(stmt)
+		case stmt[0].is("create"):
+			err = p.createIndex(stmt)
+		case stmt[0].is("alter") && len(stmt) > 1 && stmt[1].is("table"):
+			err = p.alterTable(stmt)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(p.tables) == 0 {
+		return nil, nil, fmt.Errorf("ddl has no CREATE TABLE statements")
+	}
+	return p.models(), p.warnings, nil
+}
+
+func lexDDL(src string) ([]ddlToken, error) {
+	var out []ddlToken
+	line := 1
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-', r == '#':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			start := line
+			for i += 2; i+1 < len(rs) && (rs[i] != '*' || rs[i+1] != '/'); i++ {
+				if rs[i] == '\n' {
+					line++
+				}
+			}
+			if i+1 >= len(rs) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+		case r == '\'' || r == '"' || r == '`':
+			start := line
+			var b strings.Builder
+			j := i + 1
+			for {
+				if j >= len(rs) {
+					return nil, fmt.Errorf("line %d: unterminated quote", start)
+				}
+				if rs[j] == r {
+					if j+1 < len(rs) && rs[j+1] == r {
+						b.WriteRune(r)
+						j += 2
+						continue
+					}
+					break
+				}
+				if r == '\'' && rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				if rs[j] == '\n' {
+					line++
+				}
+				b.WriteRune(rs[j])
+				j++
+			}
+			kind := byte('q')
+			if r == '\'' {
+				kind = 's'
+			}
+			out = append(out, ddlToken{kind: kind, text: b.String(), line: start})
+			i = j + 1
+		case isDDLWordRune(r):
+			j := i
+			for j < len(rs) && (isDDLWordRune(rs[j]) || (rs[j] == '.' && unicode.IsDigit(rs[i]))) {
+				j++
+			}
+			out = append(out, ddlToken{kind: 'w', text: string(rs[i:j]), line: line})
+			i = j
+		case r == ':' && i+1 < len(rs) && rs[i+1] == ':':
+			out = append(out, ddlToken{kind: 'p', text: "::", line: line})
+			i += 2
+		default:
+			out = append(out, ddlToken{kind: 'p', text: string(r), line: line})
+			i++
+		}
+	}
+	return out, nil
+}
+
+func isDDLWordRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func splitDDLStatements(tokens []ddlToken) [][]ddlToken {
+	var out [][]ddlToken
+	start := 0
+	for i, t := range tokens {
+		if t.punct(";") {
+			if i > start {
+				out = append(out, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		out = append(out, tokens[start:])
+	}
+	return out
+}
+
+// group returns the tokens inside the parentheses opening at tokens[i] and
+// the index just past the closing one.
+func group(tokens []ddlToken, i int) ([]ddlToken, int, error) {
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		switch {
+		case tokens[j].punct("("):
+			depth++
+		case tokens[j].punct(")"):
+			depth--
+			if depth == 0 {
+				return tokens[i+1 : j], j + 1, nil
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("line %d: unbalanced parentheses", tokens[i].line)
+}
+
+// splitTopLevel splits tokens at commas outside parentheses.
+func splitTopLevel(tokens []ddlToken) [][]ddlToken {
+	var out [][]ddlToken
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch {
+		case t.punct("("):
+			depth++
+		case t.punct(")"):
+			depth--
+		case t.punct(",") && depth == 0:
+			out = append(out, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(out, tokens[start:])
+}
+
+// qualifiedName reads a possibly schema-qualified name at tokens[i] and
+// returns its last part.
+func qualifiedName(tokens []ddlToken, i int) (string, int, error) {
+	if i >= len(tokens) {
+		return "", 0, fmt.Errorf("line %d: expected a table name", tokens[len(tokens)-1].line)
+	}
+	name, ok := tokens[i].ident()
+	if !ok {
+		return "", 0, fmt.Errorf("line %d: expected a table name, got %q", tokens[i].line, tokens[i].text)
+	}
+	i++
+	for i+1 < len(tokens) && tokens[i].punct(".") {
+		if name, ok = tokens[i+1].ident(); !ok {
+			return "", 0, fmt.Errorf("line %d: expected a table name after '.'", tokens[i].line)
+		}
+		i += 2
+	}
+	return name, i, nil
+}
+
+func skipWords(tokens []ddlToken, i int, words ...string) int {
+	for i < len(tokens) && tokens[i].is(words...) {
+		i++
+	}
+	return i
+}
+
+// identList reads a parenthesised list of column names.
+func identList(tokens []ddlToken, i int) ([]string, int, error) {
+	if i >= len(tokens) || !tokens[i].punct("(") {
+		line := tokens[len(tokens)-1].line
+		if i < len(tokens) {
+			line = tokens[i].line
+		}
+		return nil, 0, fmt.Errorf("line %d: expected a column list", line)
+	}
+	inner, next, err := group(tokens, i)
+	if err != nil {
+		return nil, 0, err
+	}
+	var names []string
+	for _, part := range splitTopLevel(inner) {
+		if len(part) == 0 {
+			continue
+		}
+		if name, ok := part[0].ident(); ok {
+			names = append(names, name)
+		}
+	}
+	return names, next, nil
+}
+
+func (p *ddlParser) createTable(stmt []ddlToken) error {
+	i := skipWords(stmt, 1, "temp", "temporary", "unlogged", "global", "local")
+	i = skipWords(stmt, i, "table")
+	if i+2 < len(stmt) && stmt[i].is("if") && stmt[i+1].is("not") && stmt[i+2].is("exists") {
+		i += 3
+	}
+	name, i, err := qualifiedName(stmt, i)
+	if err != nil {
+		return err
+	}
+	if i >= len(stmt) || !stmt[i].punct("(") {
+		return fmt.Errorf("line %d: expected '(' after table %s", stmt[min(i, len(stmt)-1)].line, name)
+	}
+	if p.table(name) != nil {
+		return fmt.Errorf("line %d: table %s is defined twice", stmt[i].line, name)
+	}
+	body, _, err := group(stmt, i)
+	if err != nil {
+		return err
+	}
+	t := &ddlTable{name: name}
+	p.tables = append(p.tables, t)
+	var constraints [][]ddlToken
+	for _, item := range splitTopLevel(body) {
+		if len(item) == 0 {
+			continue
+		}
+		if item[0].kind == 'w' && item[0].is("constraint", "primary", "unique", "foreign", "check", "key", "index", "fulltext", "spatial", "exclude", "like") {
+			constraints = append(constraints, item)
+			continue
+		}
+		if err := p.column(t, item); err != nil {
+			return err
+		}
+	}
+	for _, c := range constraints {
+		if err := p.tableConstraint(t, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ddlColumnStops start the constraint part of a column definition.
+var ddlColumnStops = []string{"not", "null", "default", "primary", "unique", "references", "check", "constraint",
+	"auto_increment", "generated", "collate", "comment", "on", "identity", "key"}
+
+func (p *ddlParser) column(t *ddlTable, item []ddlToken) error {
+	name, ok := item[0].ident()
+	if !ok || len(item) < 2 {
+		return fmt.Errorf("line %d: expected a column definition in table %s", item[0].line, t.name)
+	}
+	col := &ddlColumn{name: name, field: DataField{Name: name}}
+	t.columns = append(t.columns, col)
+	i, err := p.columnType(t, col, item, 1)
+	if err != nil {
+		return err
+	}
+	var notNull bool
+	for i < len(item) {
+		tok := item[i]
+		switch {
+		case tok.is("not") && i+1 < len(item) && item[i+1].is("null"):
+			notNull = true
+			i += 2
+		case tok.is("default"):
+			end := i + 1
+			for end < len(item) && !(item[end].kind == 'w' && item[end].is(ddlColumnStops...)) {
+				if item[end].punct("(") {
+					_, next, err := group(item, end)
+					if err != nil {
+						return err
+					}
+					end = next
+					continue
+				}
+				end++
+			}
+			p.columnDefault(t, col, item[i+1:end])
+			i = end
+		case tok.is("primary"):
+			col.primary = true
+			i = skipWords(item, i+1, "key")
+		case tok.is("unique"):
+			col.field.Unique = true
+			i = skipWords(item, i+1, "key")
+		case tok.is("references"):
+			next, err := p.reference(col, item, i+1)
+			if err != nil {
+				return err
+			}
+			i = next
+		case tok.is("check") && i+1 < len(item) && item[i+1].punct("("):
+			inner, next, err := group(item, i+1)
+			if err != nil {
+				return err
+			}
+			p.check(t, inner)
+			i = next
+		case tok.punct("("):
+			_, next, err := group(item, i)
+			if err != nil {
+				return err
+			}
+			i = next
+		default:
+			i++
+		}
+	}
+	if notNull || col.primary {
+		f := false
+		col.field.Nullable = &f
+	}
+	return nil
+}
+
+// columnType reads the type of col at item[i] and sets the field type,
+// length, precision and enum values.
+func (p *ddlParser) columnType(t *ddlTable, col *ddlColumn, item []ddlToken, i int) (int, error) {
+	typ, i, err := qualifiedName(item, i)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: expected a type for column %s.%s", item[min(i, len(item)-1)].line, t.name, col.name)
+	}
+	words := []string{typ}
+	var args []ddlToken
+	for i < len(item) {
+		switch {
+		case item[i].punct("(") && args == nil:
+			inner, next, err := group(item, i)
+			if err != nil {
+				return 0, err
+			}
+			args = inner
+			i = next
+		case item[i].punct("["):
+			words = append(words, "[]")
+			i = skipPunct(item, i, "[", "]")
+		case item[i].kind == 'w' && item[i].is("varying", "precision", "unsigned", "signed", "zerofill", "with", "without", "time", "zone"):
+			words = append(words, strings.ToLower(item[i].text))
+			i++
+		default:
+			return i, p.applyType(t, col, words, args)
+		}
+	}
+	return i, p.applyType(t, col, words, args)
+}
+
+func skipPunct(item []ddlToken, i int, puncts ...string) int {
+	for i < len(item) && item[i].kind == 'p' && slices.Contains(puncts, item[i].text) {
+		i++
+	}
+	return i
+}
+
+func (p *ddlParser) applyType(t *ddlTable, col *ddlColumn, words []string, args []ddlToken) error {
+	f := &col.field
+	var nums []int
+	for _, a := range args {
+		if n, err := strconv.Atoi(a.text); err == nil && a.kind == 'w' {
+			nums = append(nums, n)
+		}
+	}
+	if slices.Contains(words, "[]") {
+		f.Type = "string"
+		p.warn("Column %s.%s is an array and was imported as a string.", t.name, col.name)
+		return nil
+	}
+	switch typ := words[0]; typ {
+	case "smallint", "int", "integer", "bigint", "mediumint", "int2", "int4", "int8",
+		"serial", "bigserial", "smallserial", "serial2", "serial4", "serial8", "year":
+		f.Type = "int"
+	case "tinyint":
+		f.Type = "int"
+		if len(nums) == 1 && nums[0] == 1 {
+			f.Type = "bool"
+		}
+	case "bit":
+		f.Type = "bool"
+		if len(nums) == 1 && nums[0] > 1 {
+			f.Type = "string"
+		}
+	case "bool", "boolean":
+		f.Type = "bool"
+	case "decimal", "numeric", "dec", "fixed":
+		f.Type = "float"
+		if len(nums) > 0 {
+			f.Precision = min(nums[0], maxPrecision)
+			s := 0
+			if len(nums) > 1 {
+				s = min(nums[1], f.Precision)
+			}
+			f.Scale = &s
+		}
+	case "real", "float", "float4", "float8", "double", "money":
+		f.Type = "float"
+	case "timestamp", "timestamptz", "datetime", "date", "time", "timetz":
+		f.Type = "datetime"
+	case "varchar", "char", "character", "nvarchar", "nchar", "varbinary", "binary":
+		f.Type = "string"
+		if len(nums) > 0 && nums[0] > 0 {
+			f.MaxLength = min(nums[0], maxFieldLength)
+		}
+	case "enum", "set":
+		f.Type = "string"
+		if typ == "set" {
+			p.warn("Column %s.%s is a SET and was imported as a plain string.", t.name, col.name)
+			return nil
+		}
+		var values []string
+		for _, a := range args {
+			if a.kind == 's' {
+				values = append(values, a.text)
+			}
+		}
+		p.setEnum(t, col, values)
+	case "text", "mediumtext", "longtext", "citext", "json", "jsonb", "xml":
+		f.Type = "string"
+		f.MaxLength = ddlTextLength
+		p.warn("Column %s.%s is %s and was imported as a string of up to %d characters.", t.name, col.name, typ, ddlTextLength)
+	case "tinytext", "uuid", "inet", "cidr", "macaddr":
+		f.Type = "string"
+	default:
+		f.Type = "string"
+		if values, ok := p.enums[typ]; ok {
+			p.setEnum(t, col, values)
+			return nil
+		}
+		p.warn("Column %s.%s has type %s and was imported as a string.", t.name, col.name, strings.Join(words, " "))
+	}
+	return nil
+}
+
+func (p *ddlParser) setEnum(t *ddlTable, col *ddlColumn, values []string) {
+	if col.field.Type != "string" || len(values) == 0 {
+		return
+	}
+	for _, v := range values {
+		if !literalRegex.MatchString(v) || len([]rune(v)) > col.field.maxLength() {
+			p.warn("Column %s.%s enum was dropped because %q is not a supported value.", t.name, col.name, v)
+			return
+		}
+	}
+	var enum []string
+	for _, v := range values {
+		if !slices.Contains(enum, v) {
+			enum = append(enum, v)
+		}
+	}
+	col.field.Enum = enum
+	if col.field.Default != "" && !slices.Contains(col.field.Enum, col.field.Default) {
+		col.field.Default = ""
+	}
+}
+
+// columnDefault keeps literal defaults and CURRENT_TIMESTAMP-style defaults
+// of datetime columns; sequences and other expressions are dropped.
+func (p *ddlParser) columnDefault(t *ddlTable, col *ddlColumn, expr []ddlToken) {
+	for len(expr) > 2 && expr[0].punct("(") && expr[len(expr)-1].punct(")") {
+		expr = expr[1 : len(expr)-1]
+	}
+	if cast := slices.IndexFunc(expr, func(tok ddlToken) bool { return tok.punct("::") }); cast > 0 {
+		expr = expr[:cast]
+	}
+	if len(expr) == 0 || (len(expr) == 1 && expr[0].is("null")) {
+		return
+	}
+	f := &col.field
+	kind := fieldKind(f.Type)
+	var value string
+	switch {
+	case kind == kindDatetime && expr[0].is("current_timestamp", "now", "localtimestamp", "current_date", "current_time", "getdate"):
+		value = defaultNow
+	case len(expr) == 1 && expr[0].kind == 's':
+		value = expr[0].text
+	case len(expr) == 1 && expr[0].kind == 'w':
+		value = expr[0].text
+	case len(expr) == 2 && expr[0].punct("-") && expr[1].kind == 'w':
+		value = "-" + expr[1].text
+	}
+	if kind == kindBool {
+		switch strings.ToLower(value) {
+		case "1", "t", "true":
+			value = "true"
+		case "0", "f", "false":
+			value = "false"
+		}
+	}
+	if value == "" {
+		if len(expr) == 1 && expr[0].kind == 's' {
+			p.warn("Column %s.%s default '' was dropped because empty defaults are not supported.", t.name, col.name)
+		} else if !expr[0].is("nextval") {
+			p.warn("Column %s.%s default was dropped because it is an expression.", t.name, col.name)
+		}
+		return
+	}
+	f.Default = value
+	if msg, _ := defaultError(*f, kind); msg != "" {
+		f.Default = ""
+		p.warn("Column %s.%s default %q was dropped because it %s.", t.name, col.name, value, msg)
+	}
+}
+
+// reference reads "table [(column)]" after REFERENCES.
+func (p *ddlParser) reference(col *ddlColumn, item []ddlToken, i int) (int, error) {
+	table, i, err := qualifiedName(item, i)
+	if err != nil {
+		return 0, err
+	}
+	col.refTable, col.refCol = table, "id"
+	if i < len(item) && item[i].punct("(") {
+		cols, next, err := identList(item, i)
+		if err != nil {
+			return 0, err
+		}
+		if len(cols) == 1 {
+			col.refCol = cols[0]
+		}
+		i = next
+	}
+	return i, nil
+}
+
+// check turns "column IN ('a', 'b')" into an enum; other checks are dropped.
+func (p *ddlParser) check(t *ddlTable, expr []ddlToken) {
+	for len(expr) > 2 && expr[0].punct("(") && expr[len(expr)-1].punct(")") {
+		expr = expr[1 : len(expr)-1]
+	}
+	if len(expr) >= 3 && expr[1].is("in") && expr[2].punct("(") {
+		name, _ := expr[0].ident()
+		inner, next, err := group(expr, 2)
+		if col := t.column(name); col != nil && err == nil && next == len(expr) {
+			var values []string
+			for _, part := range splitTopLevel(inner) {
+				if len(part) != 1 || part[0].kind != 's' {
+					values = nil
+					break
+				}
+				values = append(values, part[0].text)
+			}
+			if values != nil {
+				p.setEnum(t, col, values)
+				return
+			}
+		}
+	}
+	p.warn("A CHECK constraint on table %s was dropped; only \"column IN ('a', 'b')\" checks become enums.", t.name)
+}
+
+func (p *ddlParser) tableConstraint(t *ddlTable, item []ddlToken) error {
+	i := 0
+	if item[0].is("constraint") {
+		i = 2
+	}
+	if i >= len(item) {
+		return nil
+	}
+	switch {
+	case item[i].is("primary"):
+		cols, _, err := identList(item, skipWords(item, i+1, "key"))
+		if err != nil {
+			return err
+		}
+		t.primary = cols
+	case item[i].is("unique"):
+		j := skipWords(item, i+1, "key", "index")
+		if j < len(item) && !item[j].punct("(") {
+			j++
+		}
+		cols, _, err := identList(item, j)
+		if err != nil {
+			return err
+		}
+		p.markColumns(t, cols, func(c *ddlColumn) { c.field.Unique = true }, "A UNIQUE constraint")
+	case item[i].is("key", "index"):
+		j := i + 1
+		if j < len(item) && !item[j].punct("(") {
+			j++
+		}
+		cols, _, err := identList(item, j)
+		if err != nil {
+			return err
+		}
+		p.markColumns(t, cols, func(c *ddlColumn) { c.field.Index = true }, "An index")
+	case item[i].is("foreign"):
+		j := skipWords(item, i+1, "key")
+		if j < len(item) && !item[j].punct("(") {
+			j++
+		}
+		cols, j, err := identList(item, j)
+		if err != nil {
+			return err
+		}
+		if j >= len(item) || !item[j].is("references") {
+			return fmt.Errorf("line %d: expected REFERENCES in foreign key of table %s", item[i].line, t.name)
+		}
+		ref := &ddlColumn{}
+		if _, err := p.reference(ref, item, j+1); err != nil {
+			return err
+		}
+		col := t.column(firstOr(cols))
+		if len(cols) != 1 || col == nil {
+			p.warn("A composite foreign key on table %s was dropped.", t.name)
+			return nil
+		}
+		col.refTable, col.refCol = ref.refTable, ref.refCol
+	case item[i].is("check") && i+1 < len(item) && item[i+1].punct("("):
+		inner, _, err := group(item, i+1)
+		if err != nil {
+			return err
+		}
+		p.check(t, inner)
+	}
+	return nil
+}
+
+func firstOr(cols []string) string {
+	if len(cols) == 0 {
+		return ""
+	}
+	return cols[0]
+}
+
+// markColumns applies a single-column constraint; composite ones and those
+// on expressions have no DataField equivalent.
+func (p *ddlParser) markColumns(t *ddlTable, cols []string, apply func(*ddlColumn), what string) {
+	col := t.column(firstOr(cols))
+	if len(cols) != 1 || col == nil {
+		p.warn("%s on table %s was dropped because it does not cover exactly one column.", what, t.name)
+		return
+	}
+	apply(col)
+}
+
+// createType records the values of CREATE TYPE name AS ENUM (...).
+func (p *ddlParser) createType(stmt []ddlToken) error {
+	name, i, err := qualifiedName(stmt, 2)
+	if err != nil {
+		return err
+	}
+	if i+2 >= len(stmt) || !stmt[i].is("as") || !stmt[i+1].is("enum") || !stmt[i+2].punct("(") {
+		return nil
+	}
+	inner, _, err := group(stmt, i+2)
+	if err != nil {
+		return err
+	}
+	var values []string
+	for _, v := range inner {
+		if v.kind == 's' {
+			values = append(values, v.text)
+		}
+	}
+	p.enums[name] = values
+	return nil
+}
+
+// createIndex applies CREATE [UNIQUE] INDEX ... ON table (column).
+func (p *ddlParser) createIndex(stmt []ddlToken) error {
+	unique := len(stmt) > 1 && stmt[1].is("unique")
+	on := slices.IndexFunc(stmt, func(t ddlToken) bool { return t.is("on") })
+	if on < 0 || !slices.ContainsFunc(stmt[:on], func(t ddlToken) bool { return t.is("index") }) {
+		return nil
+	}
+	name, i, err := qualifiedName(stmt, skipWords(stmt, on+1, "only"))
+	if err != nil {
+		return err
+	}
+	t := p.table(name)
+	if t == nil {
+		return nil
+	}
+	if i+1 < len(stmt) && stmt[i].is("using") {
+		i += 2
+	}
+	cols, _, err := identList(stmt, i)
+	if err != nil {
+		return err
+	}
+	if unique {
+		p.markColumns(t, cols, func(c *ddlColumn) { c.field.Unique = true }, "A unique index")
+	} else {
+		p.markColumns(t, cols, func(c *ddlColumn) { c.field.Index = true }, "An index")
+	}
+	return nil
+}
+
+// alterTable applies ALTER TABLE ... ADD [CONSTRAINT] clauses, which is how
+// pg_dump emits keys.
+func (p *ddlParser) alterTable(stmt []ddlToken) error {
+	i := skipWords(stmt, 2, "only")
+	if i+1 < len(stmt) && stmt[i].is("if") && stmt[i+1].is("exists") {
+		i += 2
+	}
+	name, i, err := qualifiedName(stmt, skipWords(stmt, i, "only"))
+	if err != nil {
+		return err
+	}
+	t := p.table(name)
+	if t == nil {
+		return nil
+	}
+	for _, clause := range splitTopLevel(stmt[i:]) {
+		if len(clause) < 2 || !clause[0].is("add") || clause[1].is("column") {
+			continue
+		}
+		if err := p.tableConstraint(t, clause[1:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// singular guesses the model name of a table, e.g. categories -> category.
+func singular(table string) string {
+	switch {
+	case strings.HasSuffix(table, "ies") && len(table) > 3:
+		return table[:len(table)-3] + "y"
+	case strings.HasSuffix(table, "sses"), strings.HasSuffix(table, "xes"), strings.HasSuffix(table, "ches"), strings.HasSuffix(table, "shes"):
+		return table[:len(table)-2]
+	case strings.HasSuffix(table, "s") && !strings.HasSuffix(table, "ss"):
+		return table[:len(table)-1]
+	}
+	return table
+}
+
+// foreignKey reports whether col becomes a belongs_to relation of t.
+func (p *ddlParser) foreignKey(t *ddlTable, col *ddlColumn, joins map[string]bool) bool {
+	if col.refTable == "" {
+		return false
+	}
+	ref := p.table(col.refTable)
+	switch {
+	case ref == nil || joins[ref.name]:
+		p.warn("Foreign key %s.%s references %s, which is not a model in the DDL; it was kept as a plain column.", t.name, col.name, col.refTable)
+	case ref == t:
+		p.warn("Foreign key %s.%s references its own table; it was kept as a plain column.", t.name, col.name)
+	case col.refCol != "id" || !strings.HasSuffix(col.name, "_id") || !sqlIdentRegex.MatchString(col.name):
+		p.warn("Foreign key %s.%s does not reference %s.id from a *_id column; it was kept as a plain column.", t.name, col.name, ref.name)
+	default:
+		return true
+	}
+	return false
+}
+
+// joinTable reports whether t only links two other tables.
+func (p *ddlParser) joinTable(t *ddlTable) bool {
+	var refs []string
+	for _, c := range t.columns {
+		switch {
+		case c.name == "id":
+		case c.refTable != "" && c.refCol == "id" && p.table(c.refTable) != nil && c.refTable != t.name:
+			refs = append(refs, c.refTable)
+		default:
+			return false
+		}
+	}
+	return len(refs) == 2 && refs[0] != refs[1]
+}
+
+func (p *ddlParser) models() []DataModel {
+	joins := map[string]bool{}
+	for _, t := range p.tables {
+		if p.joinTable(t) {
+			joins[t.name] = true
+		}
+	}
+	byTable := map[string]int{}
+	var out []DataModel
+	for _, t := range p.tables {
+		if joins[t.name] {
+			continue
+		}
+		for _, c := range t.columns {
+			if slices.Contains(t.primary, c.name) {
+				c.primary = true
+			}
+		}
+		switch id := t.column("id"); {
+		case len(t.primary) > 1:
+			p.warn("Table %s has a composite primary key; generated models use their own id column.", t.name)
+		case id == nil:
+			p.warn("Table %s has no id column; generated models add one.", t.name)
+		case fieldKind(id.field.Type) != kindInt:
+			p.warn("Table %s has a %s id column; generated models use an integer id.", t.name, id.field.Type)
+		}
+		m := DataModel{Name: singular(t.name)}
+		for _, c := range t.columns {
+			if c.name == "id" {
+				continue
+			}
+			if p.foreignKey(t, c, joins) {
+				m.Relations = append(m.Relations, DataRelation{Kind: RelationBelongsTo, Model: singular(c.refTable), ForeignKey: c.name})
+				continue
+			}
+			if c.primary && len(t.primary) <= 1 {
+				c.field.Unique = true
+			}
+			m.Fields = append(m.Fields, c.field)
+		}
+		byTable[t.name] = len(out)
+		out = append(out, m)
+	}
+	for _, t := range p.tables {
+		if !joins[t.name] {
+			continue
+		}
+		var refs []string
+		for _, c := range t.columns {
+			if c.refTable != "" {
+				refs = append(refs, c.refTable)
+			}
+		}
+		ownerIdx, ok := byTable[refs[0]]
+		if _, ok2 := byTable[refs[1]]; !ok || !ok2 {
+			p.warn("Join table %s links another join table and was dropped.", t.name)
+			continue
+		}
+		owner := &out[ownerIdx]
+		owner.Relations = append(owner.Relations, DataRelation{Kind: RelationManyToMany, Model: singular(refs[1]), JoinTable: t.name})
+	}
+	return out
+}
+
+// importDDL adds the models parsed from custom.ddl to custom.models. Models
+// declared explicitly win over a table of the same name.
+func importDDL(req GenerateRequest) (GenerateRequest, []string, error) {
+	if strings.TrimSpace(req.Custom.DDL) == "" {
+		return req, nil, nil
+	}
+	models, warnings, err := ParseDDL(req.Custom.DDL)
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add(CodeInvalidFormat, "/custom/ddl", err.Error(), ddlHint)
+		return req, nil, verr
+	}
+	declared := map[string]bool{}
+	for _, m := range req.Custom.Models {
+		declared[toPascal(m.Name)] = true
+	}
+	merged := slices.Clone(req.Custom.Models)
+	verr := &ValidationError{}
+	for _, m := range models {
+		if declared[toPascal(m.Name)] {
+			warnings = append(warnings, fmt.Sprintf("Table %s was skipped because custom.models already declares %s.", tableName(m.Name), toPascal(m.Name)))
+			continue
+		}
+		reservedModelNames(req.Language, req.Database, m, func(_, problem string) {
+			verr.add(CodeInvalidFormat, "/custom/ddl", "column "+tableName(m.Name)+"."+problem, reservedHint)
+		})
+		merged = append(merged, m)
+	}
+	if len(verr.Errors) > 0 {
+		return req, nil, verr
+	}
+	req.Custom.Models = merged
+	for i, w := range warnings {
+		warnings[i] = "custom.ddl: " + w
+	}
+	return req, warnings, nil
+}
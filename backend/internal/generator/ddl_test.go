@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const postgresDDL = `
+-- pg_dump style: keys come as ALTER TABLE statements.
+CREATE TYPE public.order_status AS ENUM ('new', 'paid', 'shipped');
+
+CREATE TABLE public.users (
+    id integer NOT NULL DEFAULT nextval('users_id_seq'::regclass),
+    email character varying(120) NOT NULL,
+    active boolean DEFAULT true NOT NULL,
+    created_at timestamp with time zone DEFAULT now(),
+    settings jsonb
+);
+
+CREATE TABLE "orders" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" integer NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    total numeric(12, 2) DEFAULT 0.00,
+    status public.order_status DEFAULT 'new'::public.order_status NOT NULL,
+    channel varchar(10) CHECK (channel IN ('web', 'store')),
+    note text DEFAULT upper('x')
+);
+
+CREATE TABLE tags (id serial PRIMARY KEY, label varchar(40) UNIQUE);
+CREATE TABLE orders_tags (
+    order_id integer REFERENCES orders(id),
+    tag_id integer REFERENCES tags(id),
+    PRIMARY KEY (order_id, tag_id)
+);
+
+ALTER TABLE ONLY public.users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+CREATE UNIQUE INDEX users_email_key ON public.users USING btree (email);
+CREATE INDEX orders_created ON orders (lower(note));
+`
+
+const mysqlDDL = "/* mysqldump */\n" +
+	"CREATE TABLE IF NOT EXISTS `categories` (\n" +
+	"  `id` int unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `name` varchar(80) NOT NULL DEFAULT 'misc',\n" +
+	"  PRIMARY KEY (`id`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+	"CREATE TABLE `products` (\n" +
+	"  `id` bigint NOT NULL AUTO_INCREMENT,\n" +
+	"  `sku` char(12) NOT NULL,\n" +
+	"  `size` enum('s','m','l') DEFAULT 'm',\n" +
+	"  `in_stock` tinyint(1) NOT NULL DEFAULT '1',\n" +
+	"  `weight` double DEFAULT NULL,\n" +
+	"  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+	"  `category_id` int unsigned DEFAULT NULL,\n" +
+	"  `parent_id` bigint DEFAULT NULL,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  UNIQUE KEY `uq_sku` (`sku`),\n" +
+	"  KEY `idx_weight` (`weight`),\n" +
+	"  CONSTRAINT `fk_category` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`),\n" +
+	"  CONSTRAINT `fk_parent` FOREIGN KEY (`parent_id`) REFERENCES `products` (`id`)\n" +
+	") ENGINE=InnoDB;\n"
+
+func TestParseDDL(t *testing.T) {
+	t.Parallel()
+
+	notNull := false
+	two, zero := 2, 0
+	cases := []struct {
+		name     string
+		ddl      string
+		models   []DataModel
+		warnings []string
+	}{
+		{
+			name: "postgres",
+			ddl:  postgresDDL,
+			models: []DataModel{
+				{Name: "user", Fields: []DataField{
+					{Name: "email", Type: "string", Nullable: &notNull, MaxLength: 120, Unique: true},
+					{Name: "active", Type: "bool", Nullable: &notNull, Default: "true"},
+					{Name: "created_at", Type: "datetime", Default: "now"},
+					{Name: "settings", Type: "string", MaxLength: ddlTextLength},
+				}},
+				{Name: "order", Fields: []DataField{
+					{Name: "total", Type: "float", Precision: 12, Scale: &two, Default: "0.00"},
+					{Name: "status", Type: "string", Nullable: &notNull, Default: "new", Enum: []string{"new", "paid", "shipped"}},
+					{Name: "channel", Type: "string", MaxLength: 10, Enum: []string{"web", "store"}},
+					{Name: "note", Type: "string", MaxLength: ddlTextLength},
+				}, Relations: []DataRelation{
+					{Kind: RelationBelongsTo, Model: "user", ForeignKey: "user_id"},
+					{Kind: RelationManyToMany, Model: "tag", JoinTable: "orders_tags"},
+				}},
+				{Name: "tag", Fields: []DataField{{Name: "label", Type: "string", MaxLength: 40, Unique: true}}},
+			},
+			warnings: []string{
+				"Column orders.note default was dropped because it is an expression.",
+				"Column users.settings is jsonb and was imported as a string of up to 4000 characters.",
+				"An index on table orders was dropped because it does not cover exactly one column.",
+			},
+		},
+		{
+			name: "mysql",
+			ddl:  mysqlDDL,
+			models: []DataModel{
+				{Name: "category", Fields: []DataField{{Name: "name", Type: "string", Nullable: &notNull, MaxLength: 80, Default: "misc"}}},
+				{Name: "product", Fields: []DataField{
+					{Name: "sku", Type: "string", Nullable: &notNull, MaxLength: 12, Unique: true},
+					{Name: "size", Type: "string", Default: "m", Enum: []string{"s", "m", "l"}},
+					{Name: "in_stock", Type: "bool", Nullable: &notNull, Default: "true"},
+					{Name: "weight", Type: "float", Index: true},
+					{Name: "updated_at", Type: "datetime", Nullable: &notNull, Default: "now"},
+					{Name: "parent_id", Type: "int"},
+				}, Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "category", ForeignKey: "category_id"}}},
+			},
+			warnings: []string{"Foreign key products.parent_id references its own table; it was kept as a plain column."},
+		},
+		{
+			name: "primary key without id",
+			ddl:  "create table settings (key_name varchar(40) primary key, amount numeric(5))",
+			models: []DataModel{{Name: "setting", Fields: []DataField{
+				{Name: "key_name", Type: "string", Nullable: &notNull, MaxLength: 40, Unique: true},
+				{Name: "amount", Type: "float", Precision: 5, Scale: &zero},
+			}}},
+			warnings: []string{"Table settings has no id column; generated models add one."},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			models, warnings, err := ParseDDL(tc.ddl)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if !reflect.DeepEqual(models, tc.models) {
+				t.Fatalf("models:\n got %+v\nwant %+v", models, tc.models)
+			}
+			for _, want := range tc.warnings {
+				if !strings.Contains(strings.Join(warnings, "\n"), want) {
+					t.Fatalf("expected warning %q, got %q", want, warnings)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDDLErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":                                 "no CREATE TABLE statements",
+		"CREATE INDEX x ON y (z);":         "no CREATE TABLE statements",
+		"CREATE TABLE users (id int":       "line 1: unbalanced parentheses",
+		"CREATE TABLE users\n(email 'x');": "line 2: expected a type for column users.email",
+		"CREATE TABLE a (id int);\nCREATE TABLE a (id int);": "line 2: table a is defined twice",
+		"CREATE TABLE a (name text DEFAULT 'x);":             "line 1: unterminated quote",
+		"/* CREATE TABLE a (id int);":                        "line 1: unterminated comment",
+	}
+	for ddl, want := range cases {
+		if _, _, err := ParseDDL(ddl); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParseDDL(%q): expected error containing %q, got %v", ddl, want, err)
+		}
+	}
+}
+
+func TestBuildImportsDDL(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	req := GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql", UseORM: true, Custom: CustomOptions{
+		DDL:    postgresDDL,
+		Models: []DataModel{{Name: "tag", Fields: []DataField{{Name: "slug", Type: "string"}}}},
+	}}
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	models := domainSource(project.Tree)
+	for _, want := range []string{"type Order struct", "Email string", "Tags []Tag `json:\"tags,omitempty\" gorm:\"many2many:orders_tags\"`", "Slug string"} {
+		if !strings.Contains(models, want) {
+			t.Fatalf("expected the domain structs to contain %q, got:\n%s", want, models)
+		}
+	}
+	if strings.Contains(models, "Label") {
+		t.Fatalf("expected the declared tag model to win over the DDL table, got:\n%s", models)
+	}
+	warnings := strings.Join(project.Warnings(), "\n")
+	for _, want := range []string{"custom.ddl: Table tags was skipped because custom.models already declares Tag.", "custom.ddl: Column orders.note default"} {
+		if !strings.Contains(warnings, want) {
+			t.Fatalf("expected warning %q, got:\n%s", want, warnings)
+		}
+	}
+
+	req.Custom.DDL = "CREATE TABLE broken ("
+	_, err = engine.Build(context.Background(), req)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/custom/ddl" {
+		t.Fatalf("expected a /custom/ddl validation error, got %v", err)
+	}
+}
+
+func TestBuildRejectsReservedDDLColumns(t *testing.T) {
+	t.Parallel()
+
+	const ddl = `CREATE TABLE lessons (id serial PRIMARY KEY, "class" text, "order" int, "type" text);`
+	engine := testEngine(t)
+	for _, tc := range []struct {
+		lang, framework, db string
+		want                []string
+	}{
+		{"python", "fastapi", "postgresql", []string{"column lessons.class is a Python keyword", "column lessons.order is a reserved word in SQL"}},
+		{"node", "express", "mongodb", []string{"column lessons.class is a reserved word in JavaScript"}},
+		{"go", "gin", "sqlite", []string{"column lessons.order is a reserved word in SQL"}},
+		{"go", "gin", "mongodb", nil},
+	} {
+		req := GenerateRequest{Language: tc.lang, Framework: tc.framework, Architecture: "mvp", Database: tc.db, Custom: CustomOptions{DDL: ddl}}
+		_, err := engine.Build(context.Background(), req)
+		var got []string
+		if verr := (*ValidationError)(nil); errors.As(err, &verr) {
+			for _, e := range verr.Errors {
+				if e.Path != "/custom/ddl" {
+					t.Fatalf("%s/%s: expected errors at /custom/ddl, got %s", tc.lang, tc.db, e.Path)
+				}
+				got = append(got, e.Message)
+			}
+		} else if err != nil {
+			t.Fatalf("%s/%s: build: %v", tc.lang, tc.db, err)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("%s/%s: expected %q, got %q", tc.lang, tc.db, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffEdits bounds the Myers search; files further apart than this are
+// treated as fully rewritten instead of spending quadratic memory on them.
+const maxDiffEdits = 4000
+
+// splitLines splits s into lines that keep their trailing newline, so joining
+// them reproduces s exactly.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matchLines returns, for every line of a, the index of the line of b it is
+// kept as in a shortest edit script, or -1 when the line is deleted.
+func matchLines(a, b []string) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		match[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		match[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	for i, j := range myersMatch(midA, midB) {
+		if j >= 0 {
+			match[prefix+i] = prefix + j
+		}
+	}
+	return match
+}
+
+func myersMatch(a, b []string) []int {
+	n, m := len(a), len(b)
+	match := make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+	if n == 0 || m == 0 {
+		return match
+	}
+
+	maxD := min(n+m, maxDiffEdits)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	trace := make([][]int, 0, 16)
+	found := -1
+	for d := 0; d <= maxD && found < 0; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = d
+				break
+			}
+		}
+	}
+	if found < 0 {
+		return match
+	}
+
+	x, y := n, m
+	for d := found; d > 0; d-- {
+		prev := trace[d]
+		at := func(k int) int { return prev[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			match[x] = y
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		match[x] = y
+	}
+	return match
+}
+
+// MergeConflict locates one conflict block in merged output by 1-based line
+// numbers, including the marker lines.
+type MergeConflict struct {
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+}
+
+// merge3 merges the changes from base to ours and from base to theirs
+// (diff3). Regions both sides changed differently are emitted with git-style
+// conflict markers, ours first.
+func merge3(base, ours, theirs, oursLabel, theirsLabel string) (string, []MergeConflict) {
+	o, a, b := splitLines(base), splitLines(ours), splitLines(theirs)
+	ma, mb := matchLines(o, a), matchLines(o, b)
+
+	var out []string
+	var conflicts []MergeConflict
+	io, ia, ib := 0, 0, 0
+	for {
+		for io < len(o) && ma[io] == ia && mb[io] == ib {
+			out = append(out, o[io])
+			io++
+			ia++
+			ib++
+		}
+		if io == len(o) && ia == len(a) && ib == len(b) {
+			break
+		}
+
+		j := io
+		for j < len(o) && (ma[j] < 0 || mb[j] < 0) {
+			j++
+		}
+		endA, endB := len(a), len(b)
+		if j < len(o) {
+			endA, endB = ma[j], mb[j]
+		}
+		chunkO, chunkA, chunkB := o[io:j], a[ia:endA], b[ib:endB]
+		switch {
+		case equalLines(chunkA, chunkO):
+			out = append(out, chunkB...)
+		case equalLines(chunkB, chunkO), equalLines(chunkA, chunkB):
+			out = append(out, chunkA...)
+		default:
+			start := len(out) + 1
+			out = append(out, fmt.Sprintf("<<<<<<< %s\n", oursLabel))
+			out = append(out, withTrailingNewline(chunkA)...)
+			out = append(out, "=======\n")
+			out = append(out, withTrailingNewline(chunkB)...)
+			out = append(out, fmt.Sprintf(">>>>>>> %s\n", theirsLabel))
+			conflicts = append(conflicts, MergeConflict{StartLine: start, EndLine: len(out)})
+		}
+		io, ia, ib = j, endA, endB
+	}
+	return strings.Join(out, ""), conflicts
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withTrailingNewline makes sure a conflict side ends with a newline so the
+// closing marker starts on its own line.
+func withTrailingNewline(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	out := append([]string(nil), lines...)
+	out[len(out)-1] += "\n"
+	return out
+}
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+func diffOps(a, b []string) []diffOp {
+	match := matchLines(a, b)
+	ops := make([]diffOp, 0, len(a)+len(b))
+	j := 0
+	for i, m := range match {
+		if m < 0 {
+			ops = append(ops, diffOp{'-', a[i]})
+			continue
+		}
+		for ; j < m; j++ {
+			ops = append(ops, diffOp{'+', b[j]})
+		}
+		ops = append(ops, diffOp{' ', a[i]})
+		j++
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// unifiedDiff renders a unified diff from oldText to newText with the given
+// number of context lines. oldName or newName may be "/dev/null" for added or
+// removed files. Identical inputs produce "".
+func unifiedDiff(oldName, newName, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffOps(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// Extend the hunk while the gap to the next change fits in context.
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+				continue
+			}
+			if i-end >= 2*context {
+				break
+			}
+		}
+		from := max(start-context, 0)
+		to := min(end+context, len(ops))
+
+		oldStart, newStart := 0, 0
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, op := range ops[from:to] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = to
+	}
+	return b.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -4,38 +4,88 @@ import (
 	"context"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 )
 
 type Engine struct {
 	registry *TemplateRegistry
+
+	packsMu sync.RWMutex
+	packs   map[string]*TemplateRegistry
 }
 
 func NewEngine(registry *TemplateRegistry) *Engine {
 	return &Engine{registry: registry}
 }
 
-func (e *Engine) Generate(_ context.Context, req GenerateRequest) (GenerateResponse, error) {
+// Project is the in-memory result of a generation run, before it is rendered
+// into scripts or archives.
+type Project struct {
+	Request      GenerateRequest
+	Tree         FileTree
+	RuleWarnings []string
+}
+
+func (e *Engine) Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	project, err := e.Build(ctx, req)
+	if err != nil {
+		return GenerateResponse{}, err
+	}
+	return BuildScripts(project.Request, project.Tree, project.RuleWarnings)
+}
+
+// Warnings merges the rule-engine corrections with generation-time warnings,
+// matching the list returned by Generate.
+func (p Project) Warnings() []string {
+	return mergeWarnings(buildGenerationWarnings(p.Request), p.RuleWarnings)
+}
+
+// Build normalizes, validates and renders the request into a FileTree.
+func (e *Engine) Build(_ context.Context, req GenerateRequest) (Project, error) {
 	req = normalize(req)
+	declared := req.Custom.Models
+	req, ddlWarnings, err := importDDL(req)
+	if err != nil {
+		return Project{}, err
+	}
+	req, openAPIWarnings, err := importOpenAPI(req)
+	if err != nil {
+		return Project{}, err
+	}
 	var ruleWarnings []string
-	var err error
 	req, ruleWarnings, err = ApplyRuleEngine(req)
 	if err != nil {
-		return GenerateResponse{}, err
+		return Project{}, err
 	}
+	ruleWarnings = slices.Concat(ddlWarnings, openAPIWarnings, ruleWarnings)
 	if err := Validate(req); err != nil {
-		return GenerateResponse{}, err
+		return Project{}, err
+	}
+	engine, err := e.forRequest(req)
+	if err != nil {
+		return Project{}, err
 	}
 
 	tree := FileTree{Files: map[string]string{}, Dirs: map[string]struct{}{}}
 	tree.Dirs["."] = struct{}{}
 
-	if err := e.generateCore(&tree, req); err != nil {
-		return GenerateResponse{}, err
+	if err := engine.generateCore(&tree, req); err != nil {
+		return Project{}, err
+	}
+	if err := applyCustomizations(&tree, req.Custom); err != nil {
+		return Project{}, err
+	}
+	// Record the models as declared: custom.openapi and custom.ddl stay in
+	// the request and are imported again on rebuild.
+	stored := req
+	stored.Custom.Models = declared
+	if err := addProjectManifest(&tree, stored, engine.registry); err != nil {
+		return Project{}, err
 	}
-	applyCustomizations(&tree, req.Custom)
 
-	return BuildScripts(req, tree, ruleWarnings)
+	return Project{Request: req, Tree: tree, RuleWarnings: ruleWarnings}, nil
 }
 
 func normalize(req GenerateRequest) GenerateRequest {
@@ -44,12 +94,26 @@ func normalize(req GenerateRequest) GenerateRequest {
 	req.Architecture = strings.ToLower(strings.TrimSpace(req.Architecture))
 	req.Database = strings.ToLower(strings.TrimSpace(req.Database))
 	req.Root.Mode = strings.ToLower(strings.TrimSpace(req.Root.Mode))
+	req.TemplatePack.Name = strings.TrimSpace(req.TemplatePack.Name)
+	req.MigrationTool = strings.ToLower(strings.TrimSpace(req.MigrationTool))
+	if req.MigrationTool == "none" {
+		req.MigrationTool = ""
+	}
+	if len(req.Custom.AddFiles) > 0 {
+		files := make([]CustomFile, len(req.Custom.AddFiles))
+		for i, f := range req.Custom.AddFiles {
+			f.Mode = strings.ToLower(strings.TrimSpace(f.Mode))
+			files[i] = f
+		}
+		req.Custom.AddFiles = files
+	}
 	if req.Database == "" {
 		req.Database = "none"
 	}
 	if req.Root.Mode == "" {
 		req.Root.Mode = "new"
 	}
+	req.Root.OnConflict = strings.ToLower(strings.TrimSpace(req.Root.OnConflict))
 	if req.Root.Mode == "new" && req.Root.Name == "" {
 		req.Root.Name = "stacksprint-generated"
 	}
@@ -95,7 +159,7 @@ func (e *Engine) generateCore(tree *FileTree, req GenerateRequest) error {
 		addFile(tree, ".env", buildEnv(req, "", 8080))
 	}
 	if isEnabled(req.FileToggles.Gitignore) {
-		addFile(tree, ".gitignore", baseGitignore(req.Language))
+		addFile(tree, ".gitignore", baseGitignore(req.Language, req.Database))
 	}
 	if isEnabled(req.FileToggles.Readme) {
 		addFile(tree, "README.md", buildREADME(req))
@@ -104,15 +168,17 @@ func (e *Engine) generateCore(tree *FileTree, req GenerateRequest) error {
 	if req.Features.GitHubActions {
 		addFile(tree, ".github/workflows/ci.yaml", buildCIPipeline(req))
 	}
-	if req.Features.Makefile {
+	if req.Features.Makefile || (req.MigrationTool != "" && req.Architecture != "microservices" && req.Language != "node") {
 		addFile(tree, "Makefile", buildMakefile(req))
 	}
-	if req.Features.Swagger {
+	if req.Features.Swagger || strings.TrimSpace(req.Custom.OpenAPI) != "" {
 		addFile(tree, "docs/openapi.yaml", buildOpenAPI(req))
 	}
-	if req.Database != "none" {
+	if req.Database != "none" && req.MigrationTool == "" {
 		addFile(tree, "migrations/001_initial.sql", sampleMigration(req.Database, req.Custom.Models))
-		addFile(tree, "db/init/001_init.sql", sampleDBInit(req.Database, req.Custom.Models))
+	}
+	if usesDBContainer(req.Database) && req.MigrationTool == "" {
+		addFile(tree, dbInitPath(req.Database), sampleDBInit(req.Database, req.Custom.Models))
 	}
 	if strings.EqualFold(req.ServiceCommunication, "grpc") {
 		addFile(tree, "proto/README.md", "# Shared proto definitions\n\nPlace your protobuf contracts here.\n")
@@ -141,6 +207,9 @@ func (e *Engine) generateMonolith(tree *FileTree, req GenerateRequest) error {
 	if req.Database != "none" {
 		addDatabaseBoilerplate(tree, req, "")
 	}
+	if req.MigrationTool != "" {
+		addMigrationFiles(tree, req, "")
+	}
 	if req.Features.JWTAuth {
 		addAuthBoilerplate(tree, req, "")
 	}
@@ -150,9 +219,6 @@ func (e *Engine) generateMonolith(tree *FileTree, req GenerateRequest) error {
 	if isEnabled(req.FileToggles.BaseRoute) {
 		addBaseRoute(tree, req, "")
 	}
-	if isEnabled(req.FileToggles.ExampleCRUD) {
-		addCRUDRoute(tree, req, "")
-	}
 	addInfraBoilerplate(tree, req, "")
 	addAutopilotBoilerplate(tree, req, "")
 	addDBRetry(tree, req, "")
@@ -190,6 +256,9 @@ func (e *Engine) generateMicroservices(tree *FileTree, req GenerateRequest) erro
 		if req.Database != "none" {
 			addDatabaseBoilerplate(tree, req, svcRoot)
 		}
+		if req.MigrationTool != "" {
+			addMigrationFiles(tree, req, svcRoot)
+		}
 		if req.Features.JWTAuth {
 			addAuthBoilerplate(tree, req, svcRoot)
 		}
@@ -199,9 +268,6 @@ func (e *Engine) generateMicroservices(tree *FileTree, req GenerateRequest) erro
 		if isEnabled(req.FileToggles.BaseRoute) {
 			addBaseRoute(tree, req, svcRoot)
 		}
-		if isEnabled(req.FileToggles.ExampleCRUD) {
-			addCRUDRoute(tree, req, svcRoot)
-		}
 		addInfraBoilerplate(tree, req, svcRoot)
 		addAutopilotBoilerplate(tree, req, svcRoot)
 		addDBRetry(tree, req, svcRoot)
@@ -214,7 +280,7 @@ func (e *Engine) generateMicroservices(tree *FileTree, req GenerateRequest) erro
 		addFile(tree, "README.md", buildREADME(req))
 	}
 	if isEnabled(req.FileToggles.Gitignore) {
-		addFile(tree, ".gitignore", baseGitignore(req.Language))
+		addFile(tree, ".gitignore", baseGitignore(req.Language, req.Database))
 	}
 	return nil
 }
@@ -229,34 +295,11 @@ func addFile(tree *FileTree, p, content string) {
 	}
 }
 
-func applyCustomizations(tree *FileTree, c CustomOptions) {
-	for _, d := range c.AddFolders {
-		tree.Dirs[filepath.ToSlash(d)] = struct{}{}
-	}
-	for _, f := range c.AddFiles {
-		p := filepath.ToSlash(strings.TrimPrefix(f.Path, "./"))
-		if existing, exists := tree.Files[p]; exists {
-			tree.Files[p] = existing + "\n\n// --- CUSTOM INJECTED CODE ---\n\n" + f.Content
-			continue
-		}
-		addFile(tree, p, f.Content)
-	}
-	for _, d := range c.RemoveFolders {
-		d = filepath.ToSlash(d)
-		delete(tree.Dirs, d)
-		for file := range tree.Files {
-			if file == d || strings.HasPrefix(file, d+"/") {
-				delete(tree.Files, file)
-			}
-		}
-	}
-	for _, f := range c.RemoveFiles {
-		delete(tree.Files, filepath.ToSlash(f))
-	}
-}
-
-func baseGitignore(lang string) string {
+func baseGitignore(lang, db string) string {
 	base := "# StackSprint\n.env\n*.log\n.DS_Store\n"
+	if db == "sqlite" {
+		base += "data/*.db\n"
+	}
 	switch lang {
 	case "go":
 		return base + "bin/\ncoverage.out\n"
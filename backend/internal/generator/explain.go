@@ -54,6 +54,20 @@ func buildDecisionEntries(req GenerateRequest, tree FileTree, ruleWarnings []str
 			Message:  fmt.Sprintf("Database=%s with ORM %s.", req.Database, orm),
 		})
 	}
+	if msg := inMemoryRepositories(req); msg != "" {
+		out = append(out, DecisionEntry{
+			Code:     "db.in_memory",
+			Category: "database",
+			Message:  msg,
+		})
+	}
+	if req.MigrationTool != "" {
+		out = append(out, DecisionEntry{
+			Code:     "db.migrations",
+			Category: "database",
+			Message:  fmt.Sprintf("Schema migrations run with %s through a one-shot compose service before the app starts.", req.MigrationTool),
+		})
+	}
 
 	infra := enabledInfra(req.Infra)
 	if len(infra) > 0 {
@@ -95,6 +109,10 @@ func buildDecisionEntries(req GenerateRequest, tree FileTree, ruleWarnings []str
 		})
 	}
 
+	if req.Root.Mode == "existing" {
+		out = append(out, conflictDecision(req, tree))
+	}
+
 	out = append(out, DecisionEntry{
 		Code:     "output.tree",
 		Category: "output",
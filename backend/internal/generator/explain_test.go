@@ -70,3 +70,33 @@ func TestBuildDecisionEntries(t *testing.T) {
 		}
 	}
 }
+
+func TestInMemoryRepositoriesAreCalledOut(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		req  GenerateRequest
+		want bool
+	}{
+		{req: GenerateRequest{Language: "go", Framework: "gin", Database: "mongodb"}, want: true},
+		{req: GenerateRequest{Language: "node", Framework: "express", Database: "postgresql"}, want: true},
+		{req: GenerateRequest{Language: "python", Framework: "fastapi", Database: "sqlite"}, want: true},
+		{req: GenerateRequest{Language: "go", Framework: "gin", Database: "postgresql"}},
+		{req: GenerateRequest{Language: "node", Framework: "express", Database: "mysql", UseORM: true}},
+		{req: GenerateRequest{Language: "node", Framework: "express", Database: "mongodb"}},
+		{req: GenerateRequest{Language: "python", Framework: "django", Database: "postgresql"}},
+		{req: GenerateRequest{Language: "node", Framework: "express", Database: "postgresql", FileToggles: FileToggleOptions{ExampleCRUD: boolPtr(false)}}},
+	}
+	for _, tc := range cases {
+		var codes []string
+		for _, d := range buildDecisionEntries(tc.req, FileTree{}, nil) {
+			codes = append(codes, d.Code)
+		}
+		if got := slices.Contains(codes, "db.in_memory"); got != tc.want {
+			t.Fatalf("%s/%s: expected db.in_memory=%v, got %v", tc.req.Language, tc.req.Database, tc.want, codes)
+		}
+		if got := len(buildGenerationWarnings(tc.req)) > 0; got != tc.want {
+			t.Fatalf("%s/%s: expected warning=%v, got %v", tc.req.Language, tc.req.Database, tc.want, buildGenerationWarnings(tc.req))
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+)
+
+type FileContent struct {
+	Path    string `json:"path"`
+	Size    int    `json:"size"`
+	SHA256  string `json:"sha256"`
+	Content string `json:"content,omitempty"`
+}
+
+type FilesResponse struct {
+	Files    []FileContent `json:"files"`
+	Warnings []string      `json:"warnings"`
+}
+
+// CollectFiles returns the rendered files in path order. Filters may be exact
+// paths, directory prefixes or path.Match globs; an empty filter list keeps
+// every file.
+func CollectFiles(tree FileTree, filters []string, withContent bool) []FileContent {
+	tree = cloneTree(tree)
+	ensureGitKeepFiles(&tree)
+
+	filters = normalizePathFilters(filters)
+	out := make([]FileContent, 0, len(tree.Files))
+	for _, f := range fileNamesSorted(tree.Files) {
+		if !matchesPathFilter(f, filters) {
+			continue
+		}
+		content := tree.Files[f]
+		entry := FileContent{
+			Path:   f,
+			Size:   len(content),
+			SHA256: contentHash(content),
+		}
+		if withContent {
+			entry.Content = content
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func normalizePathFilters(filters []string) []string {
+	out := make([]string, 0, len(filters))
+	for _, f := range filters {
+		for _, part := range strings.Split(f, ",") {
+			part = strings.TrimPrefix(strings.TrimSpace(part), "./")
+			if part == "" {
+				continue
+			}
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func matchesPathFilter(p string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if strings.ContainsAny(f, "*?[") {
+			if ok, _ := path.Match(f, p); ok {
+				return true
+			}
+			continue
+		}
+		f = strings.TrimSuffix(f, "/")
+		if p == f || strings.HasPrefix(p, f+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
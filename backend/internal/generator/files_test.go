@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCollectFilesFiltersAndHashes(t *testing.T) {
+	t.Parallel()
+
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{
+		Language:     "node",
+		Framework:    "express",
+		Architecture: "clean",
+		Root:         RootOptions{Mode: "new", Name: "files-preview"},
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+
+	all := CollectFiles(project.Tree, nil, true)
+	if len(all) == 0 {
+		t.Fatalf("expected files without filters")
+	}
+
+	got := CollectFiles(project.Tree, []string{"src/domain/", "*.json"}, true)
+	paths := make([]string, 0, len(got))
+	for _, f := range got {
+		paths = append(paths, f.Path)
+		if f.Size != len(project.Tree.Files[f.Path]) {
+			t.Fatalf("size mismatch for %s", f.Path)
+		}
+		if f.SHA256 != contentHash(project.Tree.Files[f.Path]) || len(f.SHA256) != 64 {
+			t.Fatalf("unexpected hash for %s: %q", f.Path, f.SHA256)
+		}
+	}
+	for _, want := range []string{"package.json", "src/domain/item.js"} {
+		if !hasPath(paths, want) {
+			t.Fatalf("expected %q in filtered files, got %v", want, paths)
+		}
+	}
+	if hasPath(paths, "src/index.js") {
+		t.Fatalf("did not expect src/index.js in filtered files")
+	}
+
+	noContent := CollectFiles(project.Tree, []string{"package.json"}, false)
+	if len(noContent) != 1 || noContent[0].Content != "" || noContent[0].Size == 0 {
+		t.Fatalf("expected metadata-only entry for package.json, got %+v", noContent)
+	}
+}
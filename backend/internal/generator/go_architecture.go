@@ -6,14 +6,8 @@ import (
 	"strings"
 )
 
-type templateSpec struct {
-	Template string
-	Output   string
-}
-
 func (e *Engine) generateGoMonolith(tree *FileTree, req GenerateRequest) error {
 	module := resolveGoModule(req.Root, "stacksprint/generated")
-	specs := goMonolithTemplateSpecs(req)
 	data := map[string]any{
 		"Framework":    req.Framework,
 		"Architecture": req.Architecture,
@@ -24,17 +18,12 @@ func (e *Engine) generateGoMonolith(tree *FileTree, req GenerateRequest) error {
 		"DBKind":       req.Database,
 		"Module":       module,
 		"Service":      "app",
+		"WithCRUD":     isEnabled(req.FileToggles.ExampleCRUD),
+		"Layout":       goLayouts[archTemplateName(req.Architecture)],
 	}
-	if err := e.renderSpecs(tree, specs, data, ""); err != nil {
+	if err := e.renderManifest(tree, "go/"+archTemplateName(req.Architecture), req, data, ""); err != nil {
 		return err
 	}
-	if req.Architecture == "clean" && isEnabled(req.FileToggles.ExampleCRUD) {
-		for _, model := range resolvedModels(req.Custom.Models) {
-			if err := e.renderGoCleanDynamicModel(tree, data, model); err != nil {
-				return err
-			}
-		}
-	}
 	addFile(tree, "go.mod", goModV2(req.Framework, req.Root, req.Database, req.UseORM, strings.EqualFold(req.ServiceCommunication, "grpc")))
 	if isEnabled(req.FileToggles.Config) {
 		addFile(tree, "internal/config/config.go", goConfigLoader())
@@ -51,12 +40,12 @@ func (e *Engine) generateGoMonolith(tree *FileTree, req GenerateRequest) error {
 	if strings.EqualFold(req.ServiceCommunication, "grpc") {
 		addGRPCBoilerplate(tree, req, "")
 	}
+	addOpenAPIStubs(tree, req, "")
 	return nil
 }
 
 func (e *Engine) generateGoService(tree *FileTree, req GenerateRequest, svcRoot string, svc ServiceConfig) error {
 	module := fmt.Sprintf("stacksprint/%s", svc.Name)
-	specs := goMicroserviceTemplateSpecs(req)
 	data := map[string]any{
 		"Framework":    req.Framework,
 		"Architecture": req.Architecture,
@@ -67,140 +56,54 @@ func (e *Engine) generateGoService(tree *FileTree, req GenerateRequest, svcRoot
 		"DBKind":       req.Database,
 		"Module":       module,
 		"Service":      svc.Name,
+		"WithCRUD":     isEnabled(req.FileToggles.ExampleCRUD),
+		"Layout":       goLayouts["microservice"],
 	}
-	if err := e.renderSpecs(tree, specs, data, svcRoot); err != nil {
+	if err := e.renderManifest(tree, "go/microservice", req, data, svcRoot); err != nil {
 		return err
 	}
 	addFile(tree, path.Join(svcRoot, "go.mod"), goModV2(req.Framework, RootOptions{Module: module}, req.Database, req.UseORM, strings.EqualFold(req.ServiceCommunication, "grpc")))
+	addOpenAPIStubs(tree, req, svcRoot)
 	return nil
 }
 
-func (e *Engine) renderSpecs(tree *FileTree, specs []templateSpec, data map[string]any, root string) error {
-	for _, spec := range specs {
-		body, err := e.registry.Render(spec.Template, data)
-		if err != nil {
-			return err
-		}
-		out := spec.Output
-		if root != "" {
-			out = path.Join(root, out)
-		}
-		addFile(tree, out, body)
-	}
-	return nil
+// goLayout names the packages that the templates in templates/go/shared
+// render into for one architecture folder. Paths are relative to the module.
+type goLayout struct {
+	Domain         string
+	Ports          string
+	Repository     string
+	Handler        string
+	HandlerImports []string
+	ServicePackage string
+	ServiceType    string
+	ServiceField   string
+	Receiver       string
 }
 
-func goMonolithTemplateSpecs(req GenerateRequest) []templateSpec {
-	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
-	switch req.Architecture {
-	case "clean":
-		base := []templateSpec{
-			{Template: "go/clean/cmd/server/main.tmpl", Output: "cmd/server/main.go"},
-		}
-		if withCRUD {
-			return base
-		}
-		return append(base, []templateSpec{
-			{Template: "go/clean/internal/domain/ping.tmpl", Output: "internal/domain/ping.go"},
-			{Template: "go/clean/internal/usecase/ping_usecase.tmpl", Output: "internal/usecase/ping_usecase.go"},
-			{Template: "go/clean/internal/delivery/http/ping_handler.tmpl", Output: "internal/delivery/http/ping_handler.go"},
-			{Template: "go/clean/internal/repository/ping_repository.tmpl", Output: "internal/repository/ping_repository.go"},
-		}...)
-	case "hexagonal":
-		base := []templateSpec{
-			{Template: "go/hexagonal/cmd/server/main.tmpl", Output: "cmd/server/main.go"},
-		}
-		if withCRUD {
-			return append(base, []templateSpec{
-				{Template: "go/hexagonal/core/ports/item_repository.tmpl", Output: "core/ports/item_repository.go"},
-				{Template: "go/hexagonal/core/services/item_service.tmpl", Output: "core/services/item_service.go"},
-				{Template: "go/hexagonal/adapters/primary/http/item_handler.tmpl", Output: "adapters/primary/http/item_handler.go"},
-				{Template: "go/hexagonal/adapters/secondary/database/item_repository.tmpl", Output: "adapters/secondary/database/item_repository.go"},
-			}...)
-		}
-		return append(base, []templateSpec{
-			{Template: "go/hexagonal/core/ports/ping_port.tmpl", Output: "core/ports/ping_port.go"},
-			{Template: "go/hexagonal/core/services/ping_service.tmpl", Output: "core/services/ping_service.go"},
-			{Template: "go/hexagonal/adapters/primary/http/ping_handler.tmpl", Output: "adapters/primary/http/ping_handler.go"},
-			{Template: "go/hexagonal/adapters/secondary/database/ping_repository.tmpl", Output: "adapters/secondary/database/ping_repository.go"},
-		}...)
-	case "modular-monolith":
-		return []templateSpec{
-			{Template: "go/modular/cmd/server/main.tmpl", Output: "cmd/server/main.go"},
-			{Template: "go/modular/internal/modules/catalog/module.tmpl", Output: "internal/modules/catalog/module.go"},
-			{Template: "go/modular/internal/modules/catalog/http.tmpl", Output: "internal/modules/catalog/http.go"},
-		}
-	default:
-		base := []templateSpec{
-			{Template: "go/mvp/cmd/server/main.tmpl", Output: "cmd/server/main.go"},
-		}
-		if withCRUD {
-			return append(base, []templateSpec{
-				{Template: "go/mvp/internal/handlers/item_handler.tmpl", Output: "internal/handlers/item_handler.go"},
-			}...)
-		}
-		return append(base, templateSpec{Template: "go/mvp/internal/handlers/ping_handler.tmpl", Output: "internal/handlers/ping_handler.go"})
-	}
-}
-
-func goMicroserviceTemplateSpecs(req GenerateRequest) []templateSpec {
-	base := []templateSpec{
-		{Template: "go/microservice/cmd/server/main.tmpl", Output: "cmd/server/main.go"},
-	}
-	if isEnabled(req.FileToggles.ExampleCRUD) {
-		return append(base, []templateSpec{
-			{Template: "go/microservice/internal/handlers/item_handler.tmpl", Output: "internal/handlers/item_handler.go"},
-		}...)
-	}
-	return append(base, templateSpec{Template: "go/microservice/internal/handlers/ping_handler.tmpl", Output: "internal/handlers/ping_handler.go"})
+var goLayouts = map[string]goLayout{
+	"clean": {
+		Domain: "internal/domain", Repository: "repository", Handler: "http",
+		HandlerImports: []string{"internal/domain", "internal/pagination", "internal/usecase"},
+		ServicePackage: "usecase", ServiceType: "Usecase", ServiceField: "uc", Receiver: "u",
+	},
+	"mvp": {
+		Domain: "internal/domain", Repository: "repository", Handler: "handlers",
+		HandlerImports: []string{"internal/domain", "internal/pagination", "internal/services"},
+		ServicePackage: "services", ServiceType: "Service", ServiceField: "service", Receiver: "s",
+	},
+	"microservice": {
+		Domain: "internal/domain", Repository: "repository", Handler: "handlers",
+		HandlerImports: []string{"internal/domain", "internal/pagination", "internal/services"},
+		ServicePackage: "services", ServiceType: "Service", ServiceField: "service", Receiver: "s",
+	},
+	"hexagonal": {
+		Domain: "core/domain", Ports: "core/ports", Repository: "database", Handler: "http",
+		HandlerImports: []string{"core/domain", "core/services", "internal/pagination"},
+		ServicePackage: "services", ServiceType: "Service", ServiceField: "service", Receiver: "s",
+	},
 }
 
 func isSQLDB(db string) bool {
-	return db == "postgresql" || db == "mysql"
-}
-
-func (e *Engine) renderGoCleanDynamicModel(tree *FileTree, baseData map[string]any, model DataModel) error {
-	modelNameLower := strings.ToLower(model.Name)
-	specs := []templateSpec{
-		{Template: "go/clean/internal/domain/dynamic.tmpl", Output: "internal/domain/" + modelNameLower + ".go"},
-		{Template: "go/clean/internal/usecase/dynamic.tmpl", Output: "internal/usecase/" + modelNameLower + "_usecase.go"},
-		{Template: "go/clean/internal/repository/dynamic.tmpl", Output: "internal/repository/" + modelNameLower + "_repository.go"},
-		{Template: "go/clean/internal/delivery/http/dynamic.tmpl", Output: "internal/delivery/http/" + modelNameLower + "_handler.go"},
-	}
-
-	type goTemplateField struct {
-		Name     string
-		Type     string
-		JSONName string
-	}
-	type goTemplateModel struct {
-		Name   string
-		Fields []goTemplateField
-	}
-	templModel := goTemplateModel{Name: model.Name, Fields: make([]goTemplateField, 0, len(model.Fields))}
-	for _, field := range model.Fields {
-		if strings.EqualFold(field.Name, "id") {
-			continue
-		}
-		templModel.Fields = append(templModel.Fields, goTemplateField{
-			Name:     toPascal(field.Name),
-			Type:     goType(field.Type),
-			JSONName: strings.ToLower(field.Name),
-		})
-	}
-
-	for _, spec := range specs {
-		data := make(map[string]any, len(baseData)+1)
-		for k, v := range baseData {
-			data[k] = v
-		}
-		data["Model"] = templModel
-
-		body, err := e.registry.Render(spec.Template, data)
-		if err != nil {
-			return err
-		}
-		addFile(tree, spec.Output, body)
-	}
-	return nil
+	return db == "postgresql" || db == "mysql" || db == "sqlite"
 }
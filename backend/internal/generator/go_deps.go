@@ -46,6 +46,19 @@ func goModV2(framework string, root RootOptions, db string, useORM bool, useGRPC
 			deps = append(deps, "github.com/go-sql-driver/mysql v1.8.1")
 		}
 	}
+	if db == "sqlite" {
+		if useORM {
+			deps = append(deps,
+				"gorm.io/gorm v1.25.12",
+				"github.com/glebarez/sqlite v1.11.0",
+			)
+		} else {
+			deps = append(deps, "modernc.org/sqlite v1.34.4")
+		}
+	}
+	if db == "mongodb" {
+		deps = append(deps, "go.mongodb.org/mongo-driver/v2 v2.0.0")
+	}
 	if useGRPC {
 		deps = append(deps,
 			"google.golang.org/grpc v1.69.2",
@@ -7,7 +7,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"slices"
 	"strings"
 	"testing"
@@ -191,22 +190,13 @@ func TestSmokeGeneratedScripts(t *testing.T) {
 
 func testEngine(t *testing.T) *Engine {
 	t.Helper()
-	reg, err := NewTemplateRegistry(testTemplateRoot(t))
+	reg, err := NewTemplateRegistry("")
 	if err != nil {
 		t.Fatalf("new template registry: %v", err)
 	}
 	return NewEngine(reg)
 }
 
-func testTemplateRoot(t *testing.T) string {
-	t.Helper()
-	_, currentFile, _, ok := runtime.Caller(0)
-	if !ok {
-		t.Fatalf("failed to get current file path")
-	}
-	return filepath.Clean(filepath.Join(filepath.Dir(currentFile), "..", "..", "..", "templates"))
-}
-
 func mustJSON(t *testing.T, v any) []byte {
 	t.Helper()
 	b, err := json.MarshalIndent(v, "", "  ")
@@ -0,0 +1,357 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const manifestFile = "manifest.yaml"
+
+// ArchManifest lists the files an architecture template folder renders, e.g.
+// templates/go/clean/manifest.yaml.
+type ArchManifest struct {
+	Outputs []ManifestOutput `yaml:"outputs"`
+}
+
+// ManifestOutput renders Template to Output when When matches. Template is
+// relative to the manifest folder; PerModel outputs render once per custom
+// model with {model} in Output replaced by the lower-case model name and {var}
+// by its camelCase name.
+type ManifestOutput struct {
+	Template string            `yaml:"template"`
+	Output   string            `yaml:"output"`
+	PerModel bool              `yaml:"per_model"`
+	When     ManifestCondition `yaml:"when"`
+}
+
+// ManifestCondition fields are ANDed; unset fields always match.
+type ManifestCondition struct {
+	WithCRUD  *bool    `yaml:"with_crud"`
+	UseDB     *bool    `yaml:"use_db"`
+	UseORM    *bool    `yaml:"use_orm"`
+	Framework []string `yaml:"framework"`
+	DB        []string `yaml:"db"`
+}
+
+type manifestContext struct {
+	Framework string
+	DB        string
+	WithCRUD  bool
+	UseDB     bool
+	UseORM    bool
+}
+
+func newManifestContext(req GenerateRequest) manifestContext {
+	return manifestContext{
+		Framework: req.Framework,
+		DB:        req.Database,
+		WithCRUD:  isEnabled(req.FileToggles.ExampleCRUD),
+		UseDB:     req.Database != "none",
+		UseORM:    req.UseORM,
+	}
+}
+
+func (c ManifestCondition) matches(ctx manifestContext) bool {
+	boolMatches := func(want *bool, got bool) bool { return want == nil || *want == got }
+	listMatches := func(want []string, got string) bool {
+		if len(want) == 0 {
+			return true
+		}
+		for _, w := range want {
+			if strings.EqualFold(w, got) {
+				return true
+			}
+		}
+		return false
+	}
+	return boolMatches(c.WithCRUD, ctx.WithCRUD) &&
+		boolMatches(c.UseDB, ctx.UseDB) &&
+		boolMatches(c.UseORM, ctx.UseORM) &&
+		listMatches(c.Framework, ctx.Framework) &&
+		listMatches(c.DB, ctx.DB)
+}
+
+// parseManifests loads every manifest.yaml in fsys, keyed by its folder
+// ("go/clean"), and rewrites template paths to registry paths. A manifest
+// that points at a template missing from cache is an error.
+func parseManifests(fsys fs.FS, cache map[string]*template.Template) (map[string]ArchManifest, []error) {
+	manifests := map[string]ArchManifest{}
+	var errs []error
+	walkErr := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Base(p) != manifestFile {
+			return nil
+		}
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read manifest %s: %w", p, err))
+			return nil
+		}
+		var m ArchManifest
+		if err := yaml.Unmarshal(body, &m); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse manifest %s: %w", p, err))
+			return nil
+		}
+		dir := path.Dir(p)
+		for i, out := range m.Outputs {
+			if out.Template == "" || out.Output == "" {
+				errs = append(errs, fmt.Errorf("manifest %s: output %d needs template and output", p, i))
+				continue
+			}
+			if out.PerModel && !strings.Contains(out.Output, "{model}") && !strings.Contains(out.Output, "{var}") {
+				errs = append(errs, fmt.Errorf("manifest %s: per_model output %s has no {model} or {var} placeholder", p, out.Output))
+			}
+			m.Outputs[i].Template = path.Join(dir, out.Template)
+			if _, ok := cache[m.Outputs[i].Template]; !ok {
+				errs = append(errs, fmt.Errorf("manifest %s references missing template %s", p, m.Outputs[i].Template))
+			}
+		}
+		manifests[dir] = m
+		return nil
+	})
+	if walkErr != nil {
+		errs = append(errs, fmt.Errorf("failed to walk manifests: %w", walkErr))
+	}
+	return manifests, errs
+}
+
+// renderManifest renders the outputs of the manifest in dir (e.g. "go/clean")
+// that match req into root.
+func (e *Engine) renderManifest(tree *FileTree, dir string, req GenerateRequest, data map[string]any, root string) error {
+	manifest, ok := e.registry.Manifest(dir)
+	if !ok {
+		return fmt.Errorf("manifest for %s not found: %w", dir, fs.ErrNotExist)
+	}
+	ctx := newManifestContext(req)
+	models := resolvedModels(req.Custom.Models)
+	tmplModels := templateModels(req, models)
+	data["Models"] = tmplModels
+	data["OpenAPI"] = hasOpenAPIRoutes(req)
+	for _, out := range manifest.Outputs {
+		if !out.When.matches(ctx) {
+			continue
+		}
+		if !out.PerModel {
+			if err := e.renderOutput(tree, out.Template, path.Join(root, out.Output), data); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, model := range models {
+			modelData := make(map[string]any, len(data)+1)
+			for k, v := range data {
+				modelData[k] = v
+			}
+			modelData["Model"] = tmplModels[i]
+			output := strings.NewReplacer("{model}", strings.ToLower(model.Name), "{var}", camelName(model.Name)).Replace(out.Output)
+			if err := e.renderOutput(tree, out.Template, path.Join(root, output), modelData); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Engine) renderOutput(tree *FileTree, tmpl, output string, data map[string]any) error {
+	body, err := e.registry.Render(tmpl, data)
+	if err != nil {
+		return err
+	}
+	addFile(tree, output, body)
+	return nil
+}
+
+type goTemplateField struct {
+	Name     string
+	Type     string
+	JSONName string
+	GormTag  string
+	// Required, MaxLength and EnumCases drive Validate on string fields.
+	Required  bool
+	MaxLength int
+	EnumCases string
+	EnumList  string
+	// SQLArg and SQLScan are the database/sql argument and scan destination
+	// of the field on a variable named entity. Columns with a default are
+	// left out of an insert while they hold Zero.
+	SQLArg     string
+	SQLScan    string
+	SQLDefault bool
+	Zero       string
+}
+
+type goTemplateModel struct {
+	Name string
+	// Package names the model's package in the modular layout.
+	Package   string
+	Path      string
+	Fields    []goTemplateField
+	Validates bool
+	// UsesTime is set when a datetime field needs the time import.
+	UsesTime bool
+	SQL      goSQLQueries
+	// Relations are the GORM association fields. The modular layout, with a
+	// package per model, leaves them out.
+	Relations []string
+}
+
+// goSQLQueries are the static statements of a database/sql repository, with
+// the placeholders of the selected database.
+type goSQLQueries struct {
+	Get    string
+	List   string
+	Update string
+	Delete string
+}
+
+// nodeTemplateModel carries the model class source since registry
+// templates have no custom funcs.
+type nodeTemplateModel struct {
+	Name  string
+	Var   string
+	Path  string
+	Class string
+}
+
+type pythonTemplateModel struct {
+	Name   string
+	Module string
+	Path   string
+	IDType string
+	Class  string
+}
+
+// templateModels are the .Models handed to manifest templates, one per
+// resolved model; per-model templates get theirs as .Model.
+func templateModels(req GenerateRequest, models []DataModel) []any {
+	out := make([]any, 0, len(models))
+	switch req.Language {
+	case "go":
+		links := modelLinks(models)
+		for _, m := range models {
+			gm := goModel(m, links, req.Database)
+			if req.UseORM && isSQLDB(req.Database) {
+				gm.Relations = goRelationFields(links, m.Name)
+			}
+			out = append(out, gm)
+		}
+	case "node":
+		classes := renderNodeModels(req.Custom.Models)
+		for _, m := range models {
+			out = append(out, nodeTemplateModel{Name: m.Name, Var: camelName(m.Name), Path: tableName(m.Name), Class: classes[camelName(m.Name)]})
+		}
+	case "python":
+		idType := "int"
+		if req.Database == "mongodb" {
+			idType = "str"
+		}
+		classes := pydanticSources(req.Custom.Models, idType)
+		for _, m := range models {
+			module := strings.ToLower(m.Name)
+			out = append(out, pythonTemplateModel{Name: m.Name, Module: module, Path: tableName(m.Name), IDType: idType, Class: classes[m.Name]})
+		}
+	default:
+		for _, m := range models {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func goModel(model DataModel, links []modelLink, dbKind string) goTemplateModel {
+	pkg := strings.ToLower(model.Name)
+	if token.IsKeyword(pkg) {
+		pkg += "s"
+	}
+	out := goTemplateModel{Name: model.Name, Package: pkg, Path: tableName(model.Name), Fields: make([]goTemplateField, 0, len(model.Fields))}
+	for _, field := range model.Fields {
+		if strings.EqualFold(field.Name, "id") || isForeignKeyField(links, model.Name, field.Name) {
+			continue
+		}
+		tf := goTemplateField{
+			Name:     toPascal(field.Name),
+			Type:     goType(field.Type),
+			JSONName: strings.ToLower(field.Name),
+			GormTag:  gormTag(strings.ToLower(field.Name), field),
+		}
+		if tf.Type == "string" && fieldKind(field.Type) == kindString {
+			tf.Required = field.mandatory()
+			tf.MaxLength = field.MaxLength
+			if len(field.Enum) > 0 {
+				cases := make([]string, 0, len(field.Enum)+1)
+				if !tf.Required {
+					cases = append(cases, `""`)
+				}
+				for _, v := range field.Enum {
+					cases = append(cases, strconv.Quote(v))
+				}
+				tf.EnumCases = strings.Join(cases, ", ")
+				tf.EnumList = strings.Join(field.Enum, ", ")
+			}
+			out.Validates = out.Validates || tf.Required || tf.MaxLength > 0 || tf.EnumCases != ""
+		}
+		tf.SQLArg, tf.SQLScan, tf.Zero = "entity."+tf.Name, "&entity."+tf.Name, goZero(tf.Type)
+		if fieldKind(field.Type) == kindDatetime {
+			// An unset datetime is a nil pointer, stored as NULL rather than ''.
+			tf.Type, tf.Zero = "*time.Time", "nil"
+			out.UsesTime = true
+		} else if !field.notNull() {
+			tf.SQLScan = "nullable(" + tf.SQLScan + ")"
+		}
+		tf.SQLDefault = field.Default != ""
+		out.Fields = append(out.Fields, tf)
+	}
+	for _, l := range foreignKeysOn(links, model.Name) {
+		// A nil pointer is a NULL foreign key for both GORM and database/sql.
+		name := toPascal(l.Column)
+		out.Fields = append(out.Fields, goTemplateField{
+			Name: name, Type: "*int", JSONName: l.Column, GormTag: "column:" + l.Column,
+			SQLArg: "entity." + name, SQLScan: "&entity." + name, Zero: "nil",
+		})
+	}
+	out.SQL = goSQL(out, dbKind)
+	return out
+}
+
+func goZero(goType string) string {
+	switch goType {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	default:
+		return "0"
+	}
+}
+
+func goSQL(model goTemplateModel, dbKind string) goSQLQueries {
+	bind := func(int) string { return "?" }
+	if dbKind == "postgresql" {
+		bind = func(n int) string { return "$" + strconv.Itoa(n) }
+	}
+	columns := []string{"id"}
+	sets := make([]string, 0, len(model.Fields))
+	for i, f := range model.Fields {
+		columns = append(columns, f.JSONName)
+		sets = append(sets, f.JSONName+" = "+bind(i+1))
+	}
+	if len(sets) == 0 {
+		sets = append(sets, "id = id")
+	}
+	selectFrom := "SELECT " + strings.Join(columns, ", ") + " FROM " + model.Path
+	return goSQLQueries{
+		Get:    selectFrom + " WHERE id = " + bind(1),
+		List:   selectFrom + " ORDER BY id LIMIT " + bind(1) + " OFFSET " + bind(2),
+		Update: "UPDATE " + model.Path + " SET " + strings.Join(sets, ", ") + " WHERE id = " + bind(len(model.Fields)+1),
+		Delete: "DELETE FROM " + model.Path + " WHERE id = " + bind(1),
+	}
+}
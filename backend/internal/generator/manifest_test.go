@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManifestRejectsMissingTemplate(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	manifest := filepath.Join(root, "go", "mvp", "manifest.yaml")
+	if err := os.MkdirAll(filepath.Dir(manifest), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	body := "outputs:\n  - template: cmd/server/main.tmpl\n    output: cmd/server/main.go\n  - template: internal/nope.tmpl\n    output: internal/nope.go\n"
+	if err := os.WriteFile(manifest, []byte(body), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	_, err := NewTemplateRegistry(root)
+	if err == nil || !strings.Contains(err.Error(), "go/mvp/internal/nope.tmpl") {
+		t.Fatalf("expected startup error naming the missing template, got %v", err)
+	}
+}
+
+func TestManifestOverlayAddsConditionalOutputs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "go", "mvp")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	manifest := `outputs:
+  - template: cmd/server/main.tmpl
+    output: cmd/server/main.go
+  - template: models.tmpl
+    output: internal/models/{model}.go
+    per_model: true
+  - template: gin_only.tmpl
+    output: internal/gin.txt
+    when: {framework: [gin], with_crud: true}
+  - template: gin_only.tmpl
+    output: internal/fiber.txt
+    when: {framework: [fiber]}
+`
+	files := map[string]string{
+		"manifest.yaml": manifest,
+		"models.tmpl":   "package models // {{ .Model.Name }}\n",
+		"gin_only.tmpl": "{{ .Framework }}\n",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	reg, err := NewTemplateRegistry(root)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	req := GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "mvp",
+		Database:     "none",
+		Root:         RootOptions{Mode: "new", Name: "manifest-overlay"},
+		Custom: CustomOptions{
+			Models: []DataModel{{Name: "Invoice", Fields: []DataField{{Name: "total", Type: "float"}}}},
+		},
+	}
+	project, err := NewEngine(reg).Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	if got := project.Tree.Files["internal/models/invoice.go"]; got != "package models // Invoice\n" {
+		t.Fatalf("expected per-model output, got %q", got)
+	}
+	if _, ok := project.Tree.Files["internal/gin.txt"]; !ok {
+		t.Fatalf("expected gin-only output to render")
+	}
+	if _, ok := project.Tree.Files["internal/fiber.txt"]; ok {
+		t.Fatalf("fiber-only output must not render for gin")
+	}
+	if _, ok := project.Tree.Files["internal/handlers/invoice_handler.go"]; ok {
+		t.Fatalf("overlay manifest should replace the embedded output list")
+	}
+}
@@ -2,12 +2,15 @@ package generator
 
 import (
 	"bytes"
+	"fmt"
+	"slices"
 	"strings"
 	"text/template"
 )
 
 type modelTemplateData struct {
 	Models []DataModel
+	Links  []modelLink
 }
 
 func resolvedModels(in []DataModel) []DataModel {
@@ -27,12 +30,22 @@ func resolvedModels(in []DataModel) []DataModel {
 			if ft == "" {
 				ft = "string"
 			}
-			fields = append(fields, DataField{Name: fn, Type: ft})
+			f.Name, f.Type = fn, ft
+			fields = append(fields, f)
 		}
 		if len(fields) == 0 {
 			fields = []DataField{{Name: "name", Type: "string"}}
 		}
-		clean = append(clean, DataModel{Name: toPascal(name), Fields: fields})
+		var relations []DataRelation
+		for _, r := range m.Relations {
+			relations = append(relations, DataRelation{
+				Kind:       strings.ToLower(strings.TrimSpace(r.Kind)),
+				Model:      toPascal(r.Model),
+				ForeignKey: strings.TrimSpace(r.ForeignKey),
+				JoinTable:  strings.TrimSpace(r.JoinTable),
+			})
+		}
+		clean = append(clean, DataModel{Name: toPascal(name), Fields: fields, Relations: relations})
 	}
 	if len(clean) == 0 {
 		return []DataModel{{
@@ -40,31 +53,26 @@ func resolvedModels(in []DataModel) []DataModel {
 			Fields: []DataField{{Name: "id", Type: "int"}, {Name: "name", Type: "string"}},
 		}}
 	}
-	return clean
-}
-
-func renderGoORMModels(models []DataModel) string {
-	const tpl = `package models
-
-{{ range .Models -}}
-type {{ .Name }} struct {
-{{- range .Fields }}
-	{{ goFieldName .Name }} {{ goType .Type }} ` + "`json:\"{{ .Name }}\" gorm:\"column:{{ .Name }}\"`" + `
-{{- end }}
+	return orderByDependency(clean)
 }
 
-{{ end -}}
-`
-	return renderModelTemplate(tpl, models, template.FuncMap{
-		"goType":      goType,
-		"goFieldName": func(v string) string { return toPascal(v) },
-	})
+// withoutForeignKeyFields drops plain fields that a relation renders as its
+// foreign key column.
+func withoutForeignKeyFields(models []DataModel, links []modelLink) []DataModel {
+	out := make([]DataModel, len(models))
+	for i, m := range models {
+		out[i] = m
+		out[i].Fields = slices.DeleteFunc(slices.Clone(m.Fields), func(f DataField) bool {
+			return isForeignKeyField(links, m.Name, f.Name)
+		})
+	}
+	return out
 }
 
 func renderPrismaSchema(db string, models []DataModel) string {
 	provider := "postgresql"
-	if db == "mysql" {
-		provider = "mysql"
+	if db == "mysql" || db == "sqlite" {
+		provider = db
 	}
 	const tpl = `generator client {
   provider = "prisma-client-js"
@@ -78,20 +86,32 @@ datasource db {
 {{ range .Models -}}
 model {{ .Name }} {
 {{- range .Fields }}
-  {{ prismaFieldLine .Name .Type }}
+  {{ prismaFieldLine . }}
 {{- end }}
+{{- range relationLines .Name }}
+  {{ . }}
+{{- end }}
+
+{{ range prismaIndexes . }}  {{ . }}
+{{ end }}  @@map("{{ tableName .Name }}")
 }
 
 {{ end -}}
-`
+{{ .JoinModels }}`
+	resolved := withIDField(resolvedModels(models))
+	links := modelLinks(resolved)
 	data := struct {
-		Provider string
-		Models   []DataModel
-	}{Provider: provider, Models: resolvedModels(models)}
+		Provider   string
+		Models     []DataModel
+		JoinModels string
+	}{Provider: provider, Models: withoutForeignKeyFields(resolved, links), JoinModels: prismaJoinModels(links)}
 	t, err := template.New("prisma").Funcs(template.FuncMap{
 		"prismaType":      prismaType,
 		"prismaFieldName": prismaFieldName,
-		"prismaFieldLine": prismaFieldLine,
+		"prismaFieldLine": func(f DataField) string { return prismaFieldLine(provider, f) },
+		"prismaIndexes":   prismaIndexes,
+		"tableName":       tableName,
+		"relationLines":   func(model string) []string { return prismaRelationLines(links, model) },
 	}).Parse(tpl)
 	if err != nil {
 		return ""
@@ -104,35 +124,149 @@ model {{ .Name }} {
 }
 
 func renderSQLAlchemyModels(models []DataModel) string {
-	const tpl = `from sqlalchemy.orm import DeclarativeBase, Mapped, mapped_column
-from sqlalchemy import Integer, String, Boolean, Float, DateTime
+	const tpl = `{{ imports }}
 
 class Base(DeclarativeBase):
     pass
 
-{{ range .Models -}}
+{{ joinTables }}{{ range .Models -}}
 class {{ .Name }}(Base):
     __tablename__ = "{{ tableName .Name }}"
+{{- with tableArgs . }}
+    __table_args__ = ({{ . }},)
+{{- end }}
 {{- range .Fields }}
-    {{ .Name }}: Mapped[{{ pyHint .Type }}] = mapped_column({{ sqlalchemyType .Type }})
+    {{ sqlalchemyField . }}
+{{- end }}
+{{- range relationLines .Name }}
+    {{ . }}
 {{- end }}
 
 {{ end -}}
 `
-	return renderModelTemplate(tpl, models, template.FuncMap{
-		"sqlalchemyType": sqlalchemyType,
-		"tableName":      func(v string) string { return strings.ToLower(v) + "s" },
-		"pyHint":         pythonHint,
+	resolved := withIDField(resolvedModels(models))
+	links := modelLinks(resolved)
+	return renderModelTemplate(tpl, withoutForeignKeyFields(resolved, links), links, template.FuncMap{
+		"imports":         func() string { return sqlalchemyImports(resolved, links) },
+		"joinTables":      func() string { return sqlalchemyJoinTables(links) },
+		"relationLines":   func(model string) []string { return sqlalchemyRelationLines(links, model) },
+		"sqlalchemyField": sqlalchemyField,
+		"tableArgs":       sqlalchemyTableArgs,
+		"tableName":       tableName,
 	})
 }
 
-func renderModelTemplate(tpl string, models []DataModel, funcs template.FuncMap) string {
+func sqlalchemyImports(models []DataModel, links []modelLink) string {
+	orm := "DeclarativeBase, Mapped, mapped_column"
+	core := "Integer, String, Boolean, Float, DateTime"
+	if len(links) > 0 {
+		orm += ", relationship"
+		core += ", Column, ForeignKey, Table"
+	}
+	var numeric, check, now, literal bool
+	for _, m := range models {
+		for _, f := range m.Fields {
+			numeric = numeric || (fieldKind(f.Type) == kindFloat && f.Precision > 0)
+			check = check || len(f.Enum) > 0
+			now = now || (f.Default != "" && fieldKind(f.Type) == kindDatetime)
+			literal = literal || (f.Default != "" && fieldKind(f.Type) != kindDatetime && fieldKind(f.Type) != kindString)
+		}
+	}
+	if numeric {
+		core += ", Numeric"
+	}
+	if check {
+		core += ", CheckConstraint"
+	}
+	if now {
+		core += ", func"
+	}
+	if literal {
+		core += ", text"
+	}
+	return "from sqlalchemy.orm import " + orm + "\nfrom sqlalchemy import " + core
+}
+
+func sqlalchemyField(f DataField) string {
+	if strings.EqualFold(f.Name, "id") {
+		return "id: Mapped[int] = mapped_column(Integer, primary_key=True)"
+	}
+	hint := pythonHint(f.Type)
+	args := []string{sqlalchemyType(f.Type)}
+	switch fieldKind(f.Type) {
+	case kindString:
+		// MySQL needs a VARCHAR length; match the SQL migrations.
+		args[0] = fmt.Sprintf("String(%d)", f.maxLength())
+	case kindFloat:
+		if f.Precision > 0 {
+			p, s := f.decimal()
+			args[0] = fmt.Sprintf("Numeric(%d, %d, asdecimal=False)", p, s)
+		}
+	}
+	if !f.notNull() {
+		hint += " | None"
+	}
+	if f.Unique {
+		args = append(args, "unique=True")
+	}
+	if f.indexed() {
+		args = append(args, "index=True")
+	}
+	if f.Default != "" {
+		switch fieldKind(f.Type) {
+		case kindString:
+			args = append(args, "server_default="+pythonDefault(f))
+		case kindDatetime:
+			args = append(args, "server_default=func.now()")
+		default:
+			args = append(args, `server_default=text("`+sqlDefault(f)+`")`)
+		}
+	}
+	return fmt.Sprintf("%s: Mapped[%s] = mapped_column(%s)", f.Name, hint, strings.Join(args, ", "))
+}
+
+func sqlalchemyTableArgs(m DataModel) string {
+	var checks []string
+	for _, f := range m.Fields {
+		if len(f.Enum) > 0 {
+			checks = append(checks, fmt.Sprintf("CheckConstraint(%q, name=%q)", enumCheck(f.column(), f.Enum), checkName(tableName(m.Name), f.column())))
+		}
+	}
+	return strings.Join(checks, ", ")
+}
+
+// withIDField prepends the id column the SQL migrations always create, so
+// Prisma models have a unique identifier.
+func withIDField(models []DataModel) []DataModel {
+	out := make([]DataModel, len(models))
+	for i, m := range models {
+		out[i] = m
+		if !slices.ContainsFunc(m.Fields, func(f DataField) bool { return strings.EqualFold(f.Name, "id") }) {
+			out[i].Fields = append([]DataField{{Name: "id", Type: "int"}}, m.Fields...)
+		}
+	}
+	return out
+}
+
+// camelName is the model name as a JS identifier or file name, e.g. orderItem.
+func camelName(model string) string {
+	return strings.ToLower(model[:1]) + model[1:]
+}
+
+// tableName is the SQL table or Mongo collection of a model.
+func tableName(model string) string {
+	return strings.ToLower(model) + "s"
+}
+
+// renderModelTemplate executes tpl over models that resolvedModels already
+// normalized; resolving them again would re-case multi-word names.
+func renderModelTemplate(tpl string, models []DataModel, links []modelLink, funcs template.FuncMap) string {
 	t, err := template.New("models").Funcs(funcs).Parse(tpl)
 	if err != nil {
 		return ""
 	}
 	var b bytes.Buffer
-	if err := t.Execute(&b, modelTemplateData{Models: resolvedModels(models)}); err != nil {
+	if err := t.Execute(&b, modelTemplateData{Models: models, Links: links}); err != nil {
 		return ""
 	}
 	return b.String()
@@ -176,12 +310,60 @@ func prismaFieldName(v string) string {
 	return name
 }
 
-func prismaFieldLine(name, fieldType string) string {
-	fieldName := prismaFieldName(name)
+func prismaFieldLine(provider string, f DataField) string {
+	fieldName := prismaFieldName(f.Name)
 	if strings.EqualFold(fieldName, "id") {
 		return "id Int @id @default(autoincrement())"
 	}
-	return fieldName + " " + prismaType(fieldType)
+	line := fieldName + " " + prismaType(f.Type)
+	native := ""
+	switch fieldKind(f.Type) {
+	case kindString:
+		if f.MaxLength > 0 {
+			native = fmt.Sprintf("@db.VarChar(%d)", f.MaxLength)
+		}
+	case kindFloat:
+		if f.Precision > 0 {
+			p, s := f.decimal()
+			line = fieldName + " Decimal"
+			native = fmt.Sprintf("@db.Decimal(%d, %d)", p, s)
+		}
+	}
+	if !f.notNull() {
+		line += "?"
+	}
+	if f.Unique {
+		line += " @unique"
+	}
+	if f.Default != "" {
+		switch fieldKind(f.Type) {
+		case kindString:
+			line += ` @default("` + f.Default + `")`
+		case kindDatetime:
+			line += " @default(now())"
+		default:
+			line += " @default(" + strings.ToLower(f.Default) + ")"
+		}
+	}
+	// SQLite has no native type attributes.
+	if native != "" && provider != "sqlite" {
+		line += " " + native
+	}
+	// Prisma cannot declare CHECK constraints; the migration SQL carries them.
+	if len(f.Enum) > 0 {
+		line = "/// one of: " + strings.Join(f.Enum, ", ") + "\n  " + line
+	}
+	return line
+}
+
+func prismaIndexes(m DataModel) []string {
+	var out []string
+	for _, f := range m.Fields {
+		if f.indexed() {
+			out = append(out, fmt.Sprintf("@@index([%s], map: %q)", prismaFieldName(f.Name), indexName(tableName(m.Name), f.column())))
+		}
+	}
+	return out
 }
 
 func sqlalchemyType(v string) string {
@@ -228,3 +410,196 @@ func toPascal(s string) string {
 	}
 	return strings.Join(parts, "")
 }
+
+// renderPydanticModels renders the request models of a FastAPI app; idType is
+// the Python type of the primary key.
+func renderPydanticModels(models []DataModel, idType string) string {
+	return pydanticModule(pydanticClasses(models, idType)...)
+}
+
+// pydanticSources renders each model into its own module, keyed by model name.
+func pydanticSources(models []DataModel, idType string) map[string]string {
+	classes := pydanticClasses(models, idType)
+	out := make(map[string]string, len(classes))
+	for _, c := range classes {
+		out[c.Name] = pydanticModule(c)
+	}
+	return out
+}
+
+type pydanticClass struct {
+	Name                                 string
+	Body                                 string
+	usesDatetime, usesLiteral, usesField bool
+}
+
+func pydanticClasses(models []DataModel, idType string) []pydanticClass {
+	resolved := resolvedModels(models)
+	links := modelLinks(resolved)
+	var out []pydanticClass
+	for _, m := range withoutForeignKeyFields(resolved, links) {
+		c := pydanticClass{Name: m.Name}
+		var body strings.Builder
+		fmt.Fprintf(&body, "\n\nclass %s(BaseModel):\n    id: %s | None = None\n", m.Name, idType)
+		for _, f := range m.Fields {
+			if strings.EqualFold(f.Name, "id") {
+				continue
+			}
+			line := pydanticField(f)
+			c.usesDatetime = c.usesDatetime || fieldKind(f.Type) == kindDatetime
+			c.usesLiteral = c.usesLiteral || len(f.Enum) > 0
+			c.usesField = c.usesField || strings.Contains(line, "Field(")
+			body.WriteString("    " + line + "\n")
+		}
+		for _, l := range foreignKeysOn(links, m.Name) {
+			fmt.Fprintf(&body, "    %s: int | None = None\n", l.Column)
+		}
+		c.Body = body.String()
+		out = append(out, c)
+	}
+	return out
+}
+
+func pydanticModule(classes ...pydanticClass) string {
+	var usesDatetime, usesLiteral, usesField bool
+	var body strings.Builder
+	for _, c := range classes {
+		usesDatetime = usesDatetime || c.usesDatetime
+		usesLiteral = usesLiteral || c.usesLiteral
+		usesField = usesField || c.usesField
+		body.WriteString(c.Body)
+	}
+	var head strings.Builder
+	if usesDatetime {
+		head.WriteString("from datetime import datetime\n")
+	}
+	if usesLiteral {
+		head.WriteString("from typing import Literal\n")
+	}
+	if head.Len() > 0 {
+		head.WriteString("\n")
+	}
+	if usesField {
+		head.WriteString("from pydantic import BaseModel, Field\n")
+	} else {
+		head.WriteString("from pydantic import BaseModel\n")
+	}
+	return head.String() + body.String()
+}
+
+func pydanticField(f DataField) string {
+	kind := fieldKind(f.Type)
+	hint := map[string]string{kindString: "str", kindInt: "int", kindFloat: "float", kindBool: "bool", kindDatetime: "datetime"}[kind]
+	if len(f.Enum) > 0 {
+		hint = `Literal["` + strings.Join(f.Enum, `", "`) + `"]`
+	}
+	if !f.notNull() {
+		hint += " | None"
+	}
+	var args []string
+	value := ""
+	switch {
+	case f.Default != "" && kind == kindDatetime:
+		args = append(args, "default_factory=datetime.now")
+	case f.Default != "":
+		value = pythonDefault(f)
+	case !f.notNull():
+		value = "None"
+	}
+	if kind == kindString && len(f.Enum) == 0 {
+		if f.mandatory() {
+			args = append(args, "min_length=1")
+		}
+		if f.MaxLength > 0 {
+			args = append(args, fmt.Sprintf("max_length=%d", f.MaxLength))
+		}
+	}
+	line := f.column() + ": " + hint
+	switch {
+	case len(args) > 0 && value != "":
+		return line + " = Field(default=" + value + ", " + strings.Join(args, ", ") + ")"
+	case len(args) > 0:
+		return line + " = Field(" + strings.Join(args, ", ") + ")"
+	case value != "":
+		return line + " = " + value
+	}
+	return line
+}
+
+// renderNodeModels renders one src/models file per model, keyed by file name
+// (the camelCase model name),
+// with a validate function for request bodies.
+func renderNodeModels(models []DataModel) map[string]string {
+	resolved := resolvedModels(models)
+	links := modelLinks(resolved)
+	out := make(map[string]string, len(resolved))
+	for _, m := range withoutForeignKeyFields(resolved, links) {
+		params := []string{"id"}
+		assigns := []string{"    this.id = id;"}
+		var checks []string
+		for _, f := range m.Fields {
+			if strings.EqualFold(f.Name, "id") {
+				continue
+			}
+			col := f.column()
+			param := col
+			if f.Default != "" && fieldKind(f.Type) != kindDatetime {
+				param += " = " + jsDefault(f)
+			}
+			params = append(params, param)
+			assigns = append(assigns, fmt.Sprintf("    this.%s = %s;", col, col))
+			checks = append(checks, nodeFieldChecks(f)...)
+		}
+		for _, l := range foreignKeysOn(links, m.Name) {
+			params = append(params, l.Column)
+			assigns = append(assigns, fmt.Sprintf("    this.%s = %s;", l.Column, l.Column))
+			checks = append(checks, fmt.Sprintf("    if (input.%s != null && !Number.isInteger(input.%s)) errors.push('%s must be an integer');", l.Column, l.Column, l.Column))
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "export class %s {\n  constructor({ %s } = {}) {\n%s\n  }\n\n", m.Name, strings.Join(params, ", "), strings.Join(assigns, "\n"))
+		b.WriteString("  static validate(input = {}) {\n    const errors = [];\n")
+		for _, c := range checks {
+			b.WriteString(c + "\n")
+		}
+		b.WriteString("    return errors;\n  }\n}\n")
+		out[camelName(m.Name)] = b.String()
+	}
+	return out
+}
+
+func nodeFieldChecks(f DataField) []string {
+	col := f.column()
+	v := "input." + col
+	var invalid, message string
+	switch fieldKind(f.Type) {
+	case kindInt:
+		invalid, message = "!Number.isInteger("+v+")", "must be an integer"
+	case kindFloat:
+		invalid, message = "typeof "+v+" !== 'number'", "must be a number"
+	case kindBool:
+		invalid, message = "typeof "+v+" !== 'boolean'", "must be a boolean"
+	case kindDatetime:
+		invalid, message = "Number.isNaN(Date.parse("+v+"))", "must be a date-time"
+	default:
+		invalid, message = "typeof "+v+" !== 'string'", "must be a string"
+	}
+	var out []string
+	if f.mandatory() {
+		missing := v + " == null"
+		if fieldKind(f.Type) == kindString {
+			missing += " || " + v + " === ''"
+		}
+		out = append(out,
+			fmt.Sprintf("    if (%s) errors.push('%s is required');", missing, col),
+			fmt.Sprintf("    else if (%s) errors.push('%s %s');", invalid, col, message))
+	} else {
+		out = append(out, fmt.Sprintf("    if (%s != null && %s) errors.push('%s %s');", v, invalid, col, message))
+	}
+	if f.MaxLength > 0 {
+		out = append(out, fmt.Sprintf("    if (typeof %s === 'string' && %s.length > %d) errors.push('%s must be at most %d characters');", v, v, f.MaxLength, col, f.MaxLength))
+	}
+	if len(f.Enum) > 0 {
+		out = append(out, fmt.Sprintf("    if (%s != null && !['%s'].includes(%s)) errors.push('%s must be one of: %s');", v, strings.Join(f.Enum, "', '"), v, col, strings.Join(f.Enum, ", ")))
+	}
+	return out
+}
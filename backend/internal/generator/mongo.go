@@ -0,0 +1,430 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+)
+
+type mongoField struct {
+	Name   string
+	GoName string
+	GoType string
+}
+
+// mongoIndex is a single-field index; Unique makes it a unique index.
+type mongoIndex struct {
+	Field  string
+	Unique bool
+}
+
+type mongoModel struct {
+	Name       string
+	Var        string
+	Collection string
+	Indexes    []mongoIndex
+	Fields     []mongoField
+}
+
+type mongoTemplateData struct {
+	Module string
+	Model  mongoModel
+	Models []mongoModel
+}
+
+func mongoModels(models []DataModel) []mongoModel {
+	resolved := resolvedModels(models)
+	out := make([]mongoModel, 0, len(resolved))
+	for _, m := range resolved {
+		mm := mongoModel{
+			Name:       m.Name,
+			Var:        camelName(m.Name),
+			Collection: tableName(m.Name),
+			Indexes:    mongoIndexFields(m),
+		}
+		for _, f := range m.Fields {
+			if strings.EqualFold(f.Name, "id") {
+				continue
+			}
+			mm.Fields = append(mm.Fields, mongoField{Name: f.Name, GoName: toPascal(f.Name), GoType: goType(f.Type)})
+		}
+		out = append(out, mm)
+	}
+	return out
+}
+
+// mongoIndexFields indexes the fields declared unique or index.
+func mongoIndexFields(m DataModel) []mongoIndex {
+	var out []mongoIndex
+	for _, f := range m.Fields {
+		if strings.EqualFold(f.Name, "id") || (!f.Unique && !f.Index) {
+			continue
+		}
+		out = append(out, mongoIndex{Field: f.Name, Unique: f.Unique})
+	}
+	return out
+}
+
+func addMongoBoilerplate(tree *FileTree, req GenerateRequest, root string) {
+	p := func(rel string) string { return autopilotPath(root, rel) }
+	models := mongoModels(req.Custom.Models)
+	data := mongoTemplateData{Module: goModuleFor(req, root), Models: models}
+
+	switch req.Language {
+	case "go":
+		addFile(tree, p("internal/db/connection.go"), goMongoConnection)
+		addFile(tree, p("internal/models/models.go"), renderMongoTemplate(goMongoModelsTemplate, data))
+		for _, m := range models {
+			data.Model = m
+			addFile(tree, p(fmt.Sprintf("internal/db/%s_repository.go", strings.ToLower(m.Name))), renderMongoTemplate(goMongoRepositoryTemplate, data))
+		}
+	case "node":
+		addFile(tree, p("src/db/connection.js"), nodeMongoConnection)
+		addFile(tree, p("src/db/collections.js"), renderMongoTemplate(nodeMongoCollectionsTemplate, data))
+		for _, m := range models {
+			data.Model = m
+			addFile(tree, p(fmt.Sprintf("src/repositories/%sRepository.js", m.Var)), renderMongoTemplate(nodeMongoRepositoryTemplate, data))
+		}
+	case "python":
+		addFile(tree, p("app/db/mongo.py"), pythonMongoConnection)
+		addFile(tree, p("app/repository/collections.py"), renderMongoTemplate(pythonMongoCollectionsTemplate, data))
+		for _, m := range models {
+			data.Model = m
+			addFile(tree, p(fmt.Sprintf("app/repository/%s_repository.py", strings.ToLower(m.Name))), renderMongoTemplate(pythonMongoRepositoryTemplate, data))
+		}
+	}
+}
+
+// goModuleFor returns the Go module path of the project or, under
+// microservices, of the service rooted at root.
+func goModuleFor(req GenerateRequest, root string) string {
+	if root != "" {
+		return fmt.Sprintf("stacksprint/%s", path.Base(root))
+	}
+	return resolveGoModule(req.Root, "stacksprint/generated")
+}
+
+func renderMongoTemplate(tpl string, data mongoTemplateData) string {
+	t, err := template.New("mongo").Parse(tpl)
+	if err != nil {
+		return ""
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+func mongoInitScript(models []DataModel) string {
+	var b strings.Builder
+	b.WriteString("db = db.getSiblingDB('app');\n")
+	for _, m := range mongoModels(models) {
+		b.WriteString(fmt.Sprintf("\ndb.createCollection('%s');\n", m.Collection))
+		for _, idx := range m.Indexes {
+			opts := ""
+			if idx.Unique {
+				opts = ", { unique: true }"
+			}
+			b.WriteString(fmt.Sprintf("db.getCollection('%s').createIndex({ %s: 1 }%s);\n", m.Collection, idx.Field, opts))
+		}
+	}
+	return b.String()
+}
+
+const goMongoConnection = `package db
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Connect opens a client for DATABASE_URL and returns the database named in
+// its path (default "app").
+func Connect(ctx context.Context) (*mongo.Database, error) {
+	uri := os.Getenv("DATABASE_URL")
+	if uri == "" {
+		uri = "mongodb://localhost:27017/app"
+	}
+	name := "app"
+	if u, err := url.Parse(uri); err == nil && strings.Trim(u.Path, "/") != "" {
+		name = strings.Trim(u.Path, "/")
+	}
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
+	}
+	return client.Database(name), nil
+}
+`
+
+const goMongoModelsTemplate = `package models
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+{{ range .Models }}
+type {{ .Name }} struct {
+	ID bson.ObjectID ` + "`" + `json:"id" bson:"_id,omitempty"` + "`" + `
+{{- range .Fields }}
+	{{ .GoName }} {{ .GoType }} ` + "`" + `json:"{{ .Name }}" bson:"{{ .Name }}"` + "`" + `
+{{- end }}
+}
+{{ end -}}
+`
+
+const goMongoRepositoryTemplate = `package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"{{ .Module }}/internal/models"
+)
+
+type {{ .Model.Name }}Repository struct {
+	coll *mongo.Collection
+}
+
+func New{{ .Model.Name }}Repository(database *mongo.Database) *{{ .Model.Name }}Repository {
+	return &{{ .Model.Name }}Repository{coll: database.Collection("{{ .Model.Collection }}")}
+}
+
+func (r *{{ .Model.Name }}Repository) Create(ctx context.Context, entity *models.{{ .Model.Name }}) error {
+	res, err := r.coll.InsertOne(ctx, entity)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(bson.ObjectID); ok {
+		entity.ID = id
+	}
+	return nil
+}
+
+func (r *{{ .Model.Name }}Repository) GetByID(ctx context.Context, id string) (*models.{{ .Model.Name }}, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	out := &models.{{ .Model.Name }}{}
+	if err := r.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *{{ .Model.Name }}Repository) List(ctx context.Context, limit, offset int) ([]models.{{ .Model.Name }}, error) {
+	cur, err := r.coll.Find(ctx, bson.D{}, options.Find().SetLimit(int64(limit)).SetSkip(int64(offset)))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.{{ .Model.Name }}, 0)
+	if err := cur.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *{{ .Model.Name }}Repository) Update(ctx context.Context, id string, entity *models.{{ .Model.Name }}) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return mongo.ErrNoDocuments
+	}
+	entity.ID = oid
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": oid}, entity)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *{{ .Model.Name }}Repository) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return mongo.ErrNoDocuments
+	}
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+`
+
+const nodeMongoConnection = `import { MongoClient } from 'mongodb';
+
+import { ensureCollections } from './collections.js';
+
+export const client = new MongoClient(process.env.DATABASE_URL || 'mongodb://localhost:27017/app');
+
+let database;
+
+// connect resolves to the database named in DATABASE_URL once every model's
+// collection and indexes exist. All callers share the first connection.
+export function connect() {
+  database ??= client.connect().then(async () => {
+    const db = client.db();
+    await ensureCollections(db);
+    return db;
+  });
+  return database;
+}
+`
+
+const nodeMongoCollectionsTemplate = `export const collections = [
+{{- range .Models }}
+  { name: '{{ .Collection }}', indexes: [{{ range $i, $f := .Indexes }}{{ if $i }}, {{ end }}{ key: { {{ $f.Field }}: 1 }{{ if $f.Unique }}, unique: true{{ end }} }{{ end }}] },
+{{- end }}
+];
+
+// ensureCollections creates every model's collection and indexes. It is safe
+// to run on each start.
+export async function ensureCollections(db) {
+  const existing = new Set((await db.listCollections({}, { nameOnly: true }).toArray()).map((c) => c.name));
+  for (const { name, indexes } of collections) {
+    if (!existing.has(name)) {
+      await db.createCollection(name);
+    }
+    if (indexes.length > 0) {
+      await db.collection(name).createIndexes(indexes);
+    }
+  }
+}
+`
+
+const nodeMongoRepositoryTemplate = `import { ObjectId } from 'mongodb';
+
+export class {{ .Model.Name }}Repository {
+  constructor(db) {
+    this.collection = db.collection('{{ .Model.Collection }}');
+  }
+
+  async create(data) {
+    const { insertedId } = await this.collection.insertOne({ ...data });
+    return { ...data, _id: insertedId };
+  }
+
+  async findById(id) {
+    if (!ObjectId.isValid(id)) {
+      return null;
+    }
+    return this.collection.findOne({ _id: new ObjectId(id) });
+  }
+
+  async list({ limit = 20, offset = 0 } = {}) {
+    return this.collection.find().skip(offset).limit(limit).toArray();
+  }
+
+  async update(id, data) {
+    if (!ObjectId.isValid(id)) {
+      return null;
+    }
+    const { _id, ...fields } = data;
+    return this.collection.findOneAndUpdate({ _id: new ObjectId(id) }, { $set: fields }, { returnDocument: 'after' });
+  }
+
+  async remove(id) {
+    if (!ObjectId.isValid(id)) {
+      return false;
+    }
+    const { deletedCount } = await this.collection.deleteOne({ _id: new ObjectId(id) });
+    return deletedCount > 0;
+  }
+}
+`
+
+const pythonMongoConnection = `import os
+
+from motor.motor_asyncio import AsyncIOMotorClient
+
+DATABASE_URL = os.getenv('DATABASE_URL', 'mongodb://localhost:27017/app')
+client = AsyncIOMotorClient(DATABASE_URL)
+database = client.get_default_database(default='app')
+`
+
+const pythonMongoCollectionsTemplate = `from pymongo import ASCENDING, IndexModel
+
+COLLECTIONS = {
+{{- range .Models }}
+    '{{ .Collection }}': [{{ range $i, $f := .Indexes }}{{ if $i }}, {{ end }}IndexModel([('{{ $f.Field }}', ASCENDING)]{{ if $f.Unique }}, unique=True{{ end }}){{ end }}],
+{{- end }}
+}
+
+
+async def ensure_collections(database):
+    """Create every model's collection and indexes; safe to run on each start."""
+    existing = set(await database.list_collection_names())
+    for name, indexes in COLLECTIONS.items():
+        if name not in existing:
+            await database.create_collection(name)
+        if indexes:
+            await database[name].create_indexes(indexes)
+`
+
+const pythonMongoRepositoryTemplate = `from bson import ObjectId
+from bson.errors import InvalidId
+from pymongo import ReturnDocument
+
+
+def _object_id(id: str) -> ObjectId | None:
+    try:
+        return ObjectId(id)
+    except (InvalidId, TypeError):
+        return None
+
+
+class {{ .Model.Name }}Repository:
+    def __init__(self, database):
+        self.collection = database['{{ .Model.Collection }}']
+
+    async def create(self, data: dict) -> dict:
+        result = await self.collection.insert_one(dict(data))
+        return {**data, '_id': result.inserted_id}
+
+    async def get(self, id: str) -> dict | None:
+        oid = _object_id(id)
+        if oid is None:
+            return None
+        return await self.collection.find_one({'_id': oid})
+
+    async def list(self, limit: int = 20, offset: int = 0) -> list[dict]:
+        return await self.collection.find().skip(offset).limit(limit).to_list(length=limit)
+
+    async def update(self, id: str, data: dict) -> dict | None:
+        oid = _object_id(id)
+        if oid is None:
+            return None
+        fields = {k: v for k, v in data.items() if k != '_id'}
+        return await self.collection.find_one_and_update(
+            {'_id': oid}, {'$set': fields}, return_document=ReturnDocument.AFTER
+        )
+
+    async def delete(self, id: str) -> bool:
+        oid = _object_id(id)
+        if oid is None:
+            return False
+        result = await self.collection.delete_one({'_id': oid})
+        return result.deleted_count > 0
+`
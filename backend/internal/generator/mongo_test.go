@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMongoBoilerplatePerLanguage(t *testing.T) {
+	t.Parallel()
+
+	models := []DataModel{{Name: "order", Fields: []DataField{{Name: "number", Type: "string", Unique: true}, {Name: "customer_id", Type: "string", Index: true}, {Name: "total", Type: "float"}}}}
+	cases := []struct {
+		language  string
+		framework string
+		want      map[string]string
+	}{
+		{
+			language:  "go",
+			framework: "gin",
+			want: map[string]string{
+				"go.mod":                          "go.mongodb.org/mongo-driver/v2",
+				"internal/db/connection.go":       "mongo.Connect(options.Client().ApplyURI(uri))",
+				"internal/db/order_repository.go": `database.Collection("orders")`,
+				"internal/models/models.go":       "CustomerId string `json:\"customer_id\" bson:\"customer_id\"`",
+			},
+		},
+		{
+			language:  "node",
+			framework: "express",
+			want: map[string]string{
+				"package.json":                        `"mongodb": "^6.12.0"`,
+				"src/db/connection.js":                "await ensureCollections(db);",
+				"src/db/collections.js":               "[{ key: { number: 1 }, unique: true }, { key: { customer_id: 1 } }]",
+				"src/repositories/orderRepository.js": "export class OrderRepository",
+			},
+		},
+		{
+			language:  "python",
+			framework: "fastapi",
+			want: map[string]string{
+				"requirements.txt":                   "motor==",
+				"app/db/mongo.py":                    "AsyncIOMotorClient(DATABASE_URL)",
+				"app/main.py":                        "await ensure_collections(database)",
+				"app/repository/collections.py":      "[IndexModel([('number', ASCENDING)], unique=True), IndexModel([('customer_id', ASCENDING)])]",
+				"app/repository/order_repository.py": "class OrderRepository:",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.language, func(t *testing.T) {
+			t.Parallel()
+			project, err := testEngine(t).Build(context.Background(), GenerateRequest{
+				Language:     tc.language,
+				Framework:    tc.framework,
+				Architecture: "mvp",
+				Database:     "mongodb",
+				Custom:       CustomOptions{Models: models},
+			})
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			files := project.Tree.Files
+			for p, snippet := range tc.want {
+				if !strings.Contains(files[p], snippet) {
+					t.Fatalf("expected %s to contain %q, got:\n%s", p, snippet, files[p])
+				}
+			}
+			if _, ok := files["app/db.py"]; ok {
+				t.Fatalf("app/db.py would shadow the app/db package")
+			}
+			if strings.Contains(files["internal/db/connection.go"], "database/sql") {
+				t.Fatalf("mongo projects must not fall back to database/sql")
+			}
+			if init := files["db/init/001_init.js"]; !strings.Contains(init, "db.createCollection('orders');") || !strings.Contains(init, "createIndex({ number: 1 }, { unique: true });") || !strings.Contains(init, "createIndex({ customer_id: 1 });") || strings.Contains(init, "total") {
+				t.Fatalf("expected collection init per model, got:\n%s", init)
+			}
+			if !strings.Contains(files["docker-compose.yaml"], "./db/init:/docker-entrypoint-initdb.d") {
+				t.Fatalf("expected mongo to mount the init scripts")
+			}
+		})
+	}
+}
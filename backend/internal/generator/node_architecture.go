@@ -1,12 +1,10 @@
 package generator
 
 import (
-	"fmt"
 	"path"
 )
 
 func (e *Engine) generateNodeMonolith(tree *FileTree, req GenerateRequest) error {
-	specs := nodeMonolithTemplateSpecs(req)
 	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
 	data := map[string]any{
 		"Framework":    req.Framework,
@@ -19,11 +17,11 @@ func (e *Engine) generateNodeMonolith(tree *FileTree, req GenerateRequest) error
 		"Service":      "app",
 		"WithCRUD":     withCRUD,
 	}
-	if err := e.renderSpecs(tree, specs, data, ""); err != nil {
+	if err := e.renderManifest(tree, "node/"+archTemplateName(req.Architecture), req, data, ""); err != nil {
 		return err
 	}
 
-	addFile(tree, "package.json", nodePackageJSON(req.Framework, req.Database, req.UseORM))
+	addFile(tree, "package.json", nodePackageJSON(req))
 	if isEnabled(req.FileToggles.Config) {
 		addFile(tree, "src/config/index.js", nodeConfigLoader())
 	}
@@ -37,11 +35,12 @@ func (e *Engine) generateNodeMonolith(tree *FileTree, req GenerateRequest) error
 		addFile(tree, "tests/items.test.js", nodeSampleTest(req.Framework))
 	}
 	addNodeDBBoilerplate(tree, req, "")
+	addOpenAPIStubs(tree, req, "")
 	return nil
 }
 
 func (e *Engine) generateNodeService(tree *FileTree, req GenerateRequest, svcRoot string, svc ServiceConfig) error {
-	specs := nodeMicroserviceTemplateSpecs(req)
+	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
 	data := map[string]any{
 		"Framework":    req.Framework,
 		"Architecture": req.Architecture,
@@ -51,63 +50,17 @@ func (e *Engine) generateNodeService(tree *FileTree, req GenerateRequest, svcRoo
 		"UseORM":       req.UseORM,
 		"DBKind":       req.Database,
 		"Service":      svc.Name,
+		"WithCRUD":     withCRUD,
 	}
-	if err := e.renderSpecs(tree, specs, data, svcRoot); err != nil {
+	if err := e.renderManifest(tree, "node/microservice", req, data, svcRoot); err != nil {
 		return err
 	}
-	addFile(tree, path.Join(svcRoot, "package.json"), nodePackageJSON(req.Framework, req.Database, req.UseORM))
+	addFile(tree, path.Join(svcRoot, "package.json"), nodePackageJSON(req))
 	addNodeDBBoilerplate(tree, req, svcRoot)
+	addOpenAPIStubs(tree, req, svcRoot)
 	return nil
 }
 
-func nodeMonolithTemplateSpecs(req GenerateRequest) []templateSpec {
-	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
-	switch req.Architecture {
-	case "clean":
-		base := []templateSpec{
-			{Template: "node/clean/src/index.tmpl", Output: "src/index.js"},
-		}
-		if withCRUD {
-			return append(base, []templateSpec{
-				{Template: "node/clean/src/domain/item.tmpl", Output: "src/domain/item.js"},
-				{Template: "node/clean/src/usecases/listItems.tmpl", Output: "src/usecases/listItems.js"},
-				{Template: "node/clean/src/controllers/itemController.tmpl", Output: "src/controllers/itemController.js"},
-				{Template: "node/clean/src/repositories/itemRepository.tmpl", Output: "src/repositories/itemRepository.js"},
-			}...)
-		}
-		return append(base, []templateSpec{
-			{Template: "node/clean/src/domain/ping.tmpl", Output: "src/domain/ping.js"},
-			{Template: "node/clean/src/usecases/pingUsecase.tmpl", Output: "src/usecases/pingUsecase.js"},
-			{Template: "node/clean/src/controllers/pingController.tmpl", Output: "src/controllers/pingController.js"},
-			{Template: "node/clean/src/repositories/pingRepository.tmpl", Output: "src/repositories/pingRepository.js"},
-		}...)
-	case "hexagonal":
-		base := []templateSpec{
-			{Template: "node/hexagonal/src/index.tmpl", Output: "src/index.js"},
-		}
-		if withCRUD {
-			return append(base, []templateSpec{
-				{Template: "node/hexagonal/src/core/ports/itemRepositoryPort.tmpl", Output: "src/core/ports/itemRepositoryPort.js"},
-				{Template: "node/hexagonal/src/core/services/itemService.tmpl", Output: "src/core/services/itemService.js"},
-				{Template: "node/hexagonal/src/adapters/primary/http/itemController.tmpl", Output: "src/adapters/primary/http/itemController.js"},
-				{Template: "node/hexagonal/src/adapters/secondary/database/itemRepositoryAdapter.tmpl", Output: "src/adapters/secondary/database/itemRepositoryAdapter.js"},
-			}...)
-		}
-		return append(base, []templateSpec{
-			{Template: "node/hexagonal/src/core/ports/pingPort.tmpl", Output: "src/core/ports/pingPort.js"},
-			{Template: "node/hexagonal/src/core/services/pingService.tmpl", Output: "src/core/services/pingService.js"},
-			{Template: "node/hexagonal/src/adapters/primary/http/pingController.tmpl", Output: "src/adapters/primary/http/pingController.js"},
-			{Template: "node/hexagonal/src/adapters/secondary/database/pingAdapter.tmpl", Output: "src/adapters/secondary/database/pingAdapter.js"},
-		}...)
-	default:
-		return []templateSpec{{Template: fmt.Sprintf("node/%s/main.tmpl", archTemplateName(req.Architecture)), Output: "src/index.js"}}
-	}
-}
-
-func nodeMicroserviceTemplateSpecs(_ GenerateRequest) []templateSpec {
-	return []templateSpec{{Template: "node/microservice/main.tmpl", Output: "src/index.js"}}
-}
-
 func addNodeDBBoilerplate(tree *FileTree, req GenerateRequest, root string) {
 	if !isSQLDB(req.Database) {
 		return
@@ -121,6 +74,10 @@ func addNodeDBBoilerplate(tree *FileTree, req GenerateRequest, root string) {
 		addFile(tree, prefix+"src/db/prismaClient.js", "import { PrismaClient } from '@prisma/client';\n\nexport const prisma = new PrismaClient();\n")
 		return
 	}
+	if req.Database == "sqlite" {
+		addFile(tree, prefix+"src/db/sqlClient.js", "// node:sqlite needs Node.js 22.13+ (no flag).\nimport { DatabaseSync } from 'node:sqlite';\n\nexport const db = new DatabaseSync((process.env.DATABASE_URL || 'file:data/app.db').replace(/^file:/, ''), {\n  enableForeignKeyConstraints: true,\n});\n")
+		return
+	}
 	if req.Database == "postgresql" {
 		addFile(tree, prefix+"src/db/sqlClient.js", "import pg from 'pg';\n\nconst { Pool } = pg;\nexport const db = new Pool({ connectionString: process.env.DATABASE_URL });\n")
 		return
@@ -0,0 +1,800 @@
+package generator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const openAPIHint = "Paste an OpenAPI 3 document in YAML or JSON with components/schemas and paths."
+
+const (
+	apiRef    = "ref"
+	apiArray  = "array"
+	apiObject = "object"
+	apiAny    = "any"
+)
+
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// openAPISchemas keeps schemas and properties in document order.
+type openAPISchemas []openAPINamedSchema
+
+type openAPINamedSchema struct {
+	Name   string
+	Schema *openAPISchema
+}
+
+func (s *openAPISchemas) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of schemas", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var schema openAPISchema
+		if err := node.Content[i+1].Decode(&schema); err != nil {
+			return err
+		}
+		*s = append(*s, openAPINamedSchema{Name: node.Content[i].Value, Schema: &schema})
+	}
+	return nil
+}
+
+// openAPITypes accepts both `type: string` and the 3.1 `type: [string, "null"]`.
+type openAPITypes []string
+
+func (t *openAPITypes) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = []string{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+type openAPISchema struct {
+	Ref        string           `yaml:"$ref"`
+	Type       openAPITypes     `yaml:"type"`
+	Format     string           `yaml:"format"`
+	Properties openAPISchemas   `yaml:"properties"`
+	Required   []string         `yaml:"required"`
+	Items      *openAPISchema   `yaml:"items"`
+	Enum       []any            `yaml:"enum"`
+	Default    any              `yaml:"default"`
+	MaxLength  int              `yaml:"maxLength"`
+	Nullable   bool             `yaml:"nullable"`
+	AllOf      []*openAPISchema `yaml:"allOf"`
+	OneOf      []*openAPISchema `yaml:"oneOf"`
+	AnyOf      []*openAPISchema `yaml:"anyOf"`
+	// Model set to false keeps a schema out of custom.models; it still gets a
+	// type in the handler stubs.
+	Model *bool `yaml:"x-stacksprint-model"`
+}
+
+func (s *openAPISchema) kind() string {
+	for _, t := range s.Type {
+		if t != "null" {
+			return t
+		}
+	}
+	switch {
+	case len(s.Properties) > 0:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+func (s *openAPISchema) nullable() bool { return s.Nullable || slices.Contains(s.Type, "null") }
+
+type openAPIParameter struct {
+	Ref      string         `yaml:"$ref"`
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required"`
+	Schema   *openAPISchema `yaml:"schema"`
+}
+
+// openAPIBody is a request body or a response.
+type openAPIBody struct {
+	Ref     string `yaml:"$ref"`
+	Content map[string]struct {
+		Schema *openAPISchema `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+type openAPIOperation struct {
+	OperationID string                 `yaml:"operationId"`
+	Summary     string                 `yaml:"summary"`
+	Parameters  []openAPIParameter     `yaml:"parameters"`
+	RequestBody *openAPIBody           `yaml:"requestBody"`
+	Responses   map[string]openAPIBody `yaml:"responses"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation  `yaml:"get"`
+	Post       *openAPIOperation  `yaml:"post"`
+	Put        *openAPIOperation  `yaml:"put"`
+	Patch      *openAPIOperation  `yaml:"patch"`
+	Delete     *openAPIOperation  `yaml:"delete"`
+}
+
+func (p openAPIPathItem) operation(method string) *openAPIOperation {
+	switch method {
+	case "get":
+		return p.Get
+	case "post":
+		return p.Post
+	case "put":
+		return p.Put
+	case "patch":
+		return p.Patch
+	default:
+		return p.Delete
+	}
+}
+
+// openAPIPaths keeps paths in document order.
+type openAPIPaths []struct {
+	Path string
+	Item openAPIPathItem
+}
+
+func (p *openAPIPaths) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of paths", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var item openAPIPathItem
+		if err := node.Content[i+1].Decode(&item); err != nil {
+			return err
+		}
+		*p = append(*p, struct {
+			Path string
+			Item openAPIPathItem
+		}{node.Content[i].Value, item})
+	}
+	return nil
+}
+
+type openAPIDocument struct {
+	OpenAPI    string       `yaml:"openapi"`
+	Swagger    string       `yaml:"swagger"`
+	Paths      openAPIPaths `yaml:"paths"`
+	Components struct {
+		Schemas       openAPISchemas              `yaml:"schemas"`
+		Parameters    map[string]openAPIParameter `yaml:"parameters"`
+		RequestBodies map[string]openAPIBody      `yaml:"requestBodies"`
+		Responses     map[string]openAPIBody      `yaml:"responses"`
+	} `yaml:"components"`
+}
+
+// apiType is a schema reduced to what every stub target can spell: a field
+// kind, a named type, an array, a free-form object or anything.
+type apiType struct {
+	Kind  string
+	Ref   string
+	Items *apiType
+}
+
+type apiField struct {
+	Name     string
+	Type     apiType
+	Required bool
+}
+
+// apiTypeDef is a named struct, or an alias of Alias when it is set.
+type apiTypeDef struct {
+	Name   string
+	Fields []apiField
+	Alias  *apiType
+}
+
+type apiParam struct {
+	Name     string
+	In       string
+	Type     apiType
+	Required bool
+}
+
+// apiOperation is one handler stub. Request and Response name the JSON body
+// types and are empty for operations without one.
+type apiOperation struct {
+	Name     string
+	Method   string
+	Path     string
+	Summary  string
+	Params   []apiParam
+	Request  string
+	Response string
+	Status   int
+}
+
+// apiSpec is what the handler stubs are rendered from.
+type apiSpec struct {
+	Types      []apiTypeDef
+	Operations []apiOperation
+}
+
+type openAPIReader struct {
+	doc      openAPIDocument
+	schemas  map[string]*openAPISchema
+	names    map[string]string
+	taken    map[string]bool
+	spec     apiSpec
+	models   []DataModel
+	warnings []string
+}
+
+func (r *openAPIReader) warn(format string, args ...any) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+// typeIdent turns a schema name or operationId into an exported identifier,
+// e.g. pet-list -> PetList, listPets -> ListPets.
+func typeIdent(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if r > unicode.MaxASCII {
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	out := b.String()
+	if out == "" || unicode.IsDigit(rune(out[0])) {
+		out = "T" + out
+	}
+	return out
+}
+
+// claim reserves a unique type or operation name; the Go stubs also declare
+// Handler, NewHandler and Register.
+func (r *openAPIReader) claim(name string) string {
+	out := name
+	for i := 2; r.taken[out]; i++ {
+		out = name + strconv.Itoa(i)
+	}
+	r.taken[out] = true
+	return out
+}
+
+func refName(ref, section string) (string, bool) {
+	prefix := "#/components/" + section + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", false
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(ref[len(prefix):], "~1", "/"), "~0", "~"), true
+}
+
+// target follows a schema $ref; unresolved refs yield nil.
+func (r *openAPIReader) target(s *openAPISchema) (string, *openAPISchema) {
+	for depth := 0; s != nil && s.Ref != "" && depth < 32; depth++ {
+		name, ok := refName(s.Ref, "schemas")
+		if !ok || r.schemas[name] == nil {
+			return "", nil
+		}
+		if r.schemas[name].Ref == "" {
+			return name, r.schemas[name]
+		}
+		s = r.schemas[name]
+	}
+	return "", s
+}
+
+// flatten merges the properties of an object schema and its allOf parts.
+func (r *openAPIReader) flatten(s *openAPISchema, seen map[*openAPISchema]bool) (openAPISchemas, []string, bool) {
+	if _, t := r.target(s); t != nil {
+		s = t
+	}
+	if s == nil || seen[s] {
+		return nil, nil, false
+	}
+	seen[s] = true
+	if s.kind() != "object" && len(s.AllOf) == 0 {
+		return nil, nil, false
+	}
+	props := slices.Clone(s.Properties)
+	required := slices.Clone(s.Required)
+	for _, part := range s.AllOf {
+		more, req, ok := r.flatten(part, seen)
+		if !ok {
+			continue
+		}
+		for _, p := range more {
+			if !slices.ContainsFunc(props, func(q openAPINamedSchema) bool { return q.Name == p.Name }) {
+				props = append(props, p)
+			}
+		}
+		required = append(required, req...)
+	}
+	return props, required, true
+}
+
+func (r *openAPIReader) typeOf(s *openAPISchema) apiType {
+	if s == nil {
+		return apiType{Kind: apiAny}
+	}
+	if s.Ref != "" {
+		name, ok := refName(s.Ref, "schemas")
+		if !ok || r.names[name] == "" {
+			return apiType{Kind: apiAny}
+		}
+		return apiType{Kind: apiRef, Ref: r.names[name]}
+	}
+	if len(s.AllOf) == 1 && len(s.Properties) == 0 {
+		return r.typeOf(s.AllOf[0])
+	}
+	switch s.kind() {
+	case "integer":
+		return apiType{Kind: kindInt}
+	case "number":
+		return apiType{Kind: kindFloat}
+	case "boolean":
+		return apiType{Kind: kindBool}
+	case "string":
+		return apiType{Kind: kindString}
+	case "array":
+		items := r.typeOf(s.Items)
+		return apiType{Kind: apiArray, Items: &items}
+	case "object":
+		return apiType{Kind: apiObject}
+	}
+	return apiType{Kind: apiAny}
+}
+
+func (r *openAPIReader) fields(s *openAPISchema) ([]apiField, bool) {
+	props, required, ok := r.flatten(s, map[*openAPISchema]bool{})
+	if !ok {
+		return nil, false
+	}
+	out := make([]apiField, 0, len(props))
+	for _, p := range props {
+		out = append(out, apiField{Name: p.Name, Type: r.typeOf(p.Schema), Required: slices.Contains(required, p.Name)})
+	}
+	return out, true
+}
+
+// bodyType names the JSON body of a request or response: a referenced
+// schema is used as is, anything else gets a type called name.
+func (r *openAPIReader) bodyType(body *openAPIBody, section, name string) string {
+	if body == nil {
+		return ""
+	}
+	if body.Ref != "" {
+		ref, ok := refName(body.Ref, section)
+		if !ok {
+			return ""
+		}
+		resolved, ok := r.componentBody(section, ref)
+		if !ok {
+			return ""
+		}
+		body = &resolved
+	}
+	var schema *openAPISchema
+	for _, media := range []string{"application/json", "*/*"} {
+		if c, ok := body.Content[media]; ok {
+			schema = c.Schema
+			break
+		}
+	}
+	if schema == nil {
+		for _, media := range slices.Sorted(maps.Keys(body.Content)) {
+			if strings.HasSuffix(media, "+json") {
+				schema = body.Content[media].Schema
+				break
+			}
+		}
+	}
+	if schema == nil {
+		return ""
+	}
+	if t := r.typeOf(schema); t.Kind == apiRef {
+		return t.Ref
+	}
+	name = r.claim(name)
+	if fields, ok := r.fields(schema); ok {
+		r.spec.Types = append(r.spec.Types, apiTypeDef{Name: name, Fields: fields})
+		return name
+	}
+	alias := r.typeOf(schema)
+	r.spec.Types = append(r.spec.Types, apiTypeDef{Name: name, Alias: &alias})
+	return name
+}
+
+func (r *openAPIReader) componentBody(section, name string) (openAPIBody, bool) {
+	if section == "requestBodies" {
+		b, ok := r.doc.Components.RequestBodies[name]
+		return b, ok
+	}
+	b, ok := r.doc.Components.Responses[name]
+	return b, ok
+}
+
+// success picks the lowest 2xx response, falling back to default.
+func success(responses map[string]openAPIBody) (int, *openAPIBody) {
+	best := 0
+	for code := range responses {
+		n, err := strconv.Atoi(strings.NewReplacer("X", "0", "x", "0").Replace(code))
+		if err == nil && n >= 200 && n < 300 && (best == 0 || n < best) {
+			best = n
+		}
+	}
+	for code, body := range responses {
+		n, _ := strconv.Atoi(strings.NewReplacer("X", "0", "x", "0").Replace(code))
+		if best != 0 && n == best {
+			return best, &body
+		}
+	}
+	if body, ok := responses["default"]; ok {
+		return 200, &body
+	}
+	return 200, nil
+}
+
+// params merges the path item and operation parameters; path parameters the
+// document forgot to declare are strings.
+func (r *openAPIReader) params(path string, shared, own []openAPIParameter) []apiParam {
+	var out []apiParam
+	for _, p := range slices.Concat(shared, own) {
+		if p.Ref != "" {
+			name, ok := refName(p.Ref, "parameters")
+			if !ok {
+				continue
+			}
+			p = r.doc.Components.Parameters[name]
+		}
+		if p.Name == "" || (p.In != "path" && p.In != "query") {
+			continue
+		}
+		t := r.typeOf(p.Schema)
+		if _, s := r.target(p.Schema); s != nil && p.Schema.Ref != "" {
+			t = r.typeOf(s)
+		}
+		if t.Kind != kindInt && t.Kind != kindFloat && t.Kind != kindBool {
+			t = apiType{Kind: kindString}
+		}
+		param := apiParam{Name: p.Name, In: p.In, Type: t, Required: p.Required || p.In == "path"}
+		if i := slices.IndexFunc(out, func(q apiParam) bool { return q.Name == p.Name && q.In == p.In }); i >= 0 {
+			out[i] = param
+			continue
+		}
+		out = append(out, param)
+	}
+	for _, m := range pathParamRegex.FindAllStringSubmatch(path, -1) {
+		if !slices.ContainsFunc(out, func(p apiParam) bool { return p.In == "path" && p.Name == m[1] }) {
+			out = append(out, apiParam{Name: m[1], In: "path", Type: apiType{Kind: kindString}, Required: true})
+		}
+	}
+	return out
+}
+
+func (r *openAPIReader) operations() {
+	for _, entry := range r.doc.Paths {
+		for _, method := range openAPIMethods {
+			op := entry.Item.operation(method)
+			if op == nil {
+				continue
+			}
+			name := op.OperationID
+			if name == "" {
+				name = method + " " + strings.NewReplacer("{", "", "}", "").Replace(entry.Path)
+			}
+			name = r.claim(typeIdent(name))
+			status, response := success(op.Responses)
+			r.spec.Operations = append(r.spec.Operations, apiOperation{
+				Name:     name,
+				Method:   method,
+				Path:     entry.Path,
+				Summary:  strings.Join(strings.Fields(op.Summary), " "),
+				Params:   r.params(entry.Path, entry.Item.Parameters, op.Parameters),
+				Request:  r.bodyType(op.RequestBody, "requestBodies", name+"Request"),
+				Response: r.bodyType(response, "responses", name+"Response"),
+				Status:   status,
+			})
+		}
+	}
+}
+
+// modelName is the custom.models name of a schema, e.g. OrderItem -> order_item.
+func modelName(schema string) string { return snakeCase(typeIdent(schema)) }
+
+// columnName turns a property into a column, e.g. createdAt -> created_at.
+func columnName(prop string) string {
+	s := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return '_'
+		}
+		return r
+	}, snakeCase(prop))
+	return strings.ToLower(strings.Trim(s, "_"))
+}
+
+// isModel reports whether a component schema becomes a custom model.
+func (r *openAPIReader) isModel(name string) bool {
+	s := r.schemas[name]
+	if s == nil || s.Ref != "" || (s.Model != nil && !*s.Model) {
+		return false
+	}
+	_, _, ok := r.flatten(s, map[*openAPISchema]bool{})
+	return ok
+}
+
+// modelRef returns the model schema a property points at.
+func (r *openAPIReader) modelRef(s *openAPISchema) string {
+	if s == nil {
+		return ""
+	}
+	if len(s.AllOf) == 1 {
+		s = s.AllOf[0]
+	}
+	if name, _ := r.target(s); name != "" && r.isModel(name) {
+		return name
+	}
+	return ""
+}
+
+func (r *openAPIReader) model(name string) DataModel {
+	m := DataModel{Name: modelName(name)}
+	props, required, _ := r.flatten(r.schemas[name], map[*openAPISchema]bool{})
+	for _, p := range props {
+		column := columnName(p.Name)
+		if column == "id" {
+			continue
+		}
+		where := name + "." + p.Name
+		if !sqlIdentRegex.MatchString(column) {
+			r.warn("Property %s has no usable column name and was skipped.", where)
+			continue
+		}
+		if ref := r.modelRef(p.Schema); ref != "" {
+			if ref == name {
+				r.warn("Property %s references its own schema; it was kept as a plain %s_id column.", where, column)
+				m.Fields = append(m.Fields, DataField{Name: column + "_id", Type: kindInt})
+				continue
+			}
+			m.Relations = append(m.Relations, DataRelation{Kind: RelationBelongsTo, Model: modelName(ref), ForeignKey: column + "_id"})
+			continue
+		}
+		if p.Schema != nil && p.Schema.kind() == "array" {
+			if ref := r.modelRef(p.Schema.Items); ref != "" && ref != name {
+				m.Relations = append(m.Relations, r.collection(name, ref))
+				continue
+			}
+		}
+		s := p.Schema
+		if _, t := r.target(s); t != nil {
+			s = t
+		}
+		field, ok := r.field(where, column, s)
+		if !ok {
+			continue
+		}
+		field.Required = slices.Contains(required, p.Name) && !s.nullable()
+		m.Fields = append(m.Fields, field)
+	}
+	return m
+}
+
+// collection relates name to the model ref listed in one of its array
+// properties: many_to_many when ref lists name back, has_many otherwise.
+func (r *openAPIReader) collection(name, ref string) DataRelation {
+	props, _, _ := r.flatten(r.schemas[ref], map[*openAPISchema]bool{})
+	for _, p := range props {
+		if p.Schema != nil && p.Schema.kind() == "array" && r.modelRef(p.Schema.Items) == name {
+			return DataRelation{Kind: RelationManyToMany, Model: modelName(ref)}
+		}
+	}
+	for _, p := range props {
+		if r.modelRef(p.Schema) == name {
+			return DataRelation{Kind: RelationHasMany, Model: modelName(ref), ForeignKey: columnName(p.Name) + "_id"}
+		}
+	}
+	return DataRelation{Kind: RelationHasMany, Model: modelName(ref)}
+}
+
+func (r *openAPIReader) field(where, column string, s *openAPISchema) (DataField, bool) {
+	f := DataField{Name: column}
+	switch kind := s.kind(); {
+	case kind == "integer":
+		f.Type = kindInt
+	case kind == "number":
+		f.Type = kindFloat
+	case kind == "boolean":
+		f.Type = kindBool
+	case kind == "string" && (s.Format == "date-time" || s.Format == "date"):
+		f.Type = kindDatetime
+	case kind == "string":
+		f.Type = kindString
+		f.MaxLength = s.MaxLength
+		if f.MaxLength > maxFieldLength {
+			r.warn("Property %s maxLength %d was lowered to %d.", where, f.MaxLength, maxFieldLength)
+			f.MaxLength = maxFieldLength
+		}
+	default:
+		what := map[string]string{"array": "an array", "object": "an inline object"}[kind]
+		if what == "" {
+			what = "a composite schema"
+		}
+		r.warn("Property %s is %s and was not imported as a column.", where, what)
+		return f, false
+	}
+	if f.Type == kindString && len(s.Enum) > 0 {
+		for _, v := range s.Enum {
+			if v != nil {
+				f.Enum = append(f.Enum, fmt.Sprint(v))
+			}
+		}
+		for k := range f.Enum {
+			check := f
+			check.Default = f.Enum[k]
+			check.Enum = nil
+			if msg, _ := defaultError(check, kindString); msg != "" {
+				r.warn("Property %s enum was dropped because value %q %s.", where, f.Enum[k], msg)
+				f.Enum = nil
+				break
+			}
+		}
+	}
+	if s.Default != nil && f.Type != kindDatetime {
+		f.Default = fmt.Sprint(s.Default)
+		if msg, _ := defaultError(f, f.Type); msg != "" {
+			r.warn("Property %s default was dropped because %q %s.", where, f.Default, msg)
+			f.Default = ""
+		}
+	}
+	return f, true
+}
+
+func readOpenAPI(doc string) (*openAPIReader, error) {
+	r := &openAPIReader{schemas: map[string]*openAPISchema{}, names: map[string]string{}, taken: map[string]bool{"Handler": true, "NewHandler": true, "Register": true}}
+	if err := yaml.Unmarshal([]byte(doc), &r.doc); err != nil {
+		return nil, err
+	}
+	switch {
+	case r.doc.Swagger != "":
+		return nil, fmt.Errorf("swagger %s documents are not supported; convert the document to OpenAPI 3", r.doc.Swagger)
+	case !strings.HasPrefix(r.doc.OpenAPI, "3."):
+		return nil, fmt.Errorf("expected an OpenAPI 3 document, got openapi %q", r.doc.OpenAPI)
+	case len(r.doc.Paths) == 0 && len(r.doc.Components.Schemas) == 0:
+		return nil, fmt.Errorf("the document defines no paths and no components/schemas")
+	}
+	for _, s := range r.doc.Components.Schemas {
+		r.schemas[s.Name] = s.Schema
+		r.names[s.Name] = r.claim(typeIdent(s.Name))
+	}
+	for _, s := range r.doc.Components.Schemas {
+		name := r.names[s.Name]
+		if fields, ok := r.fields(s.Schema); ok {
+			r.spec.Types = append(r.spec.Types, apiTypeDef{Name: name, Fields: fields})
+		} else {
+			alias := r.typeOf(s.Schema)
+			r.spec.Types = append(r.spec.Types, apiTypeDef{Name: name, Alias: &alias})
+		}
+		if r.isModel(s.Name) {
+			r.models = append(r.models, r.model(s.Name))
+		}
+	}
+	r.operations()
+	return r, nil
+}
+
+// ParseOpenAPI converts the component schemas of an OpenAPI 3 document into
+// custom models. Object schemas become models, $ref properties belongs_to
+// relations and arrays of $ref has_many or many_to_many relations; the
+// warnings list what could not be carried over.
+func ParseOpenAPI(doc string) ([]DataModel, []string, error) {
+	r, err := readOpenAPI(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.models, r.warnings, nil
+}
+
+// openAPIRoutes drops the operations whose paths the generated health check
+// or CRUD routes already serve.
+func openAPIRoutes(req GenerateRequest, ops []apiOperation) ([]apiOperation, []string) {
+	reserved := map[string]string{"/health": "the generated health check"}
+	if req.Framework == "django" {
+		reserved = map[string]string{"/api/health": "the generated health check"}
+	}
+	switch {
+	case isEnabled(req.FileToggles.ExampleCRUD):
+		for _, m := range resolvedModels(req.Custom.Models) {
+			reserved["/api/v1/"+tableName(m.Name)] = "the generated " + m.Name + " CRUD routes"
+		}
+	case (req.Architecture == "clean" || req.Architecture == "hexagonal") && req.Language != "go" && req.Framework != "django":
+		reserved["/ping"] = "the generated ping route"
+	}
+	var kept []apiOperation
+	var warnings []string
+	for _, op := range ops {
+		path := strings.TrimSuffix(op.Path, "/")
+		reason := reserved[path]
+		for prefix, why := range reserved {
+			if strings.HasPrefix(path, prefix+"/") && strings.HasPrefix(prefix, "/api/v1/") {
+				reason = why
+			}
+		}
+		if reason != "" {
+			warnings = append(warnings, fmt.Sprintf("Operation %s %s is served by %s; no stub was generated.", strings.ToUpper(op.Method), op.Path, reason))
+			continue
+		}
+		kept = append(kept, op)
+	}
+	return kept, warnings
+}
+
+// importOpenAPI adds the models of custom.openapi to custom.models, like
+// importDDL, and reports the operations that get no handler stub.
+func importOpenAPI(req GenerateRequest) (GenerateRequest, []string, error) {
+	if strings.TrimSpace(req.Custom.OpenAPI) == "" {
+		return req, nil, nil
+	}
+	r, err := readOpenAPI(req.Custom.OpenAPI)
+	if err != nil {
+		verr := &ValidationError{}
+		verr.add(CodeInvalidFormat, "/custom/openapi", err.Error(), openAPIHint)
+		return req, nil, verr
+	}
+	declared := map[string]bool{}
+	for _, m := range req.Custom.Models {
+		declared[toPascal(m.Name)] = true
+	}
+	warnings := r.warnings
+	merged := slices.Clone(req.Custom.Models)
+	verr := &ValidationError{}
+	for _, m := range r.models {
+		if declared[toPascal(m.Name)] {
+			warnings = append(warnings, fmt.Sprintf("Schema %s was skipped because custom.models already declares %s.", typeIdent(m.Name), toPascal(m.Name)))
+			continue
+		}
+		reservedModelNames(req.Language, req.Database, m, func(_, problem string) {
+			verr.add(CodeInvalidFormat, "/custom/openapi", "property "+typeIdent(m.Name)+"."+problem, reservedHint)
+		})
+		merged = append(merged, m)
+	}
+	if len(verr.Errors) > 0 {
+		return req, nil, verr
+	}
+	req.Custom.Models = merged
+	_, skipped := openAPIRoutes(req, r.spec.Operations)
+	warnings = append(warnings, skipped...)
+	for i, w := range warnings {
+		warnings[i] = "custom.openapi: " + w
+	}
+	return req, warnings, nil
+}
+
+// openAPISpec returns the stubs to render for req, nil without custom.openapi.
+func openAPISpec(req GenerateRequest) *apiSpec {
+	if strings.TrimSpace(req.Custom.OpenAPI) == "" {
+		return nil
+	}
+	r, err := readOpenAPI(req.Custom.OpenAPI)
+	if err != nil {
+		return nil
+	}
+	spec := r.spec
+	spec.Operations, _ = openAPIRoutes(req, spec.Operations)
+	return &spec
+}
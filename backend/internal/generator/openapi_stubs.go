@@ -0,0 +1,680 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	pathParamRegex = regexp.MustCompile(`\{([^{}/]+)\}`)
+	jsIdentRegex   = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+)
+
+var pythonKeywords = []string{"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield"}
+
+var jsReserved = []string{"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "export", "extends", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "return", "super", "switch", "this", "throw", "try", "typeof", "var", "void", "while", "with", "yield", "let", "static", "enum", "await"}
+
+// hasOpenAPIRoutes reports whether the entrypoints mount handler stubs.
+func hasOpenAPIRoutes(req GenerateRequest) bool {
+	spec := openAPISpec(req)
+	return spec != nil && len(spec.Operations) > 0
+}
+
+// addOpenAPIStubs renders the types and handler stubs of custom.openapi in
+// the idiom of the chosen framework; the entrypoints mount them when the
+// OpenAPI template flag is set.
+func addOpenAPIStubs(tree *FileTree, req GenerateRequest, root string) {
+	spec := openAPISpec(req)
+	if spec == nil || (len(spec.Types) == 0 && len(spec.Operations) == 0) {
+		return
+	}
+	switch {
+	case req.Language == "go":
+		addFile(tree, path.Join(root, "internal/openapi/types.go"), goOpenAPITypes(spec))
+		if len(spec.Operations) > 0 {
+			addFile(tree, path.Join(root, "internal/openapi/handlers.go"), goOpenAPIHandlers(spec, req.Framework))
+		}
+	case req.Language == "node":
+		addFile(tree, path.Join(root, "src/openapi/types.js"), nodeOpenAPITypes(spec))
+		if len(spec.Operations) > 0 {
+			addFile(tree, path.Join(root, "src/openapi/routes.js"), nodeOpenAPIRoutes(spec, req.Framework))
+		}
+	case req.Framework == "django":
+		addFile(tree, path.Join(root, "api/openapi_serializers.py"), djangoOpenAPISerializers(spec))
+		if len(spec.Operations) > 0 {
+			views, urls := djangoOpenAPIViews(spec)
+			addFile(tree, path.Join(root, "api/openapi_views.py"), views)
+			addFile(tree, path.Join(root, "api/openapi_urls.py"), urls)
+		}
+	default:
+		addFile(tree, path.Join(root, "app/openapi/schemas.py"), pythonOpenAPISchemas(spec))
+		if len(spec.Operations) > 0 {
+			addFile(tree, path.Join(root, "app/openapi/routes.py"), pythonOpenAPIRoutes(spec))
+		}
+	}
+}
+
+func (s *apiSpec) typeDef(name string) *apiTypeDef {
+	for i := range s.Types {
+		if s.Types[i].Name == name {
+			return &s.Types[i]
+		}
+	}
+	return nil
+}
+
+// resolve follows aliases down to a struct reference or a plain type.
+func (s *apiSpec) resolve(t apiType) apiType {
+	for depth := 0; t.Kind == apiRef && depth < 32; depth++ {
+		def := s.typeDef(t.Ref)
+		if def == nil || def.Alias == nil {
+			return t
+		}
+		t = *def.Alias
+	}
+	return t
+}
+
+// colonPaths converts {param} paths to :param routes. Routers with a single
+// tree (gin, find-my-way) want one name per wildcard position, so the first
+// name seen at a position wins.
+func colonPaths(ops []apiOperation) []string {
+	names := map[string]string{}
+	out := make([]string, len(ops))
+	for i, op := range ops {
+		segs := strings.Split(op.Path, "/")
+		prefix := ""
+		for j, seg := range segs {
+			m := pathParamRegex.FindStringSubmatch(seg)
+			if m == nil || m[0] != seg {
+				prefix += "/" + seg
+				continue
+			}
+			prefix += "/:"
+			if names[prefix] == "" {
+				names[prefix] = m[1]
+			}
+			segs[j] = ":" + names[prefix]
+		}
+		out[i] = strings.Join(segs, "/")
+	}
+	return out
+}
+
+// stubDoc describes an operation for the comment above its handler.
+func stubDoc(op apiOperation, route string) string {
+	doc := strings.ToUpper(op.Method) + " " + route
+	if op.Summary != "" {
+		doc += ": " + op.Summary
+	}
+	var query []string
+	for _, p := range op.Params {
+		if p.In == "query" {
+			query = append(query, p.Name)
+		}
+	}
+	if len(query) > 0 {
+		doc += ". Query: " + strings.Join(query, ", ")
+	}
+	if op.Response != "" {
+		doc += fmt.Sprintf(". Responds %d with %s", op.Status, op.Response)
+	}
+	return strings.TrimSuffix(doc, ".") + "."
+}
+
+func goAPIType(t apiType) string {
+	switch t.Kind {
+	case kindInt:
+		return "int"
+	case kindFloat:
+		return "float64"
+	case kindBool:
+		return "bool"
+	case kindString:
+		return "string"
+	case apiArray:
+		return "[]" + goAPIType(*t.Items)
+	case apiObject:
+		return "map[string]any"
+	case apiRef:
+		return t.Ref
+	}
+	return "any"
+}
+
+func goOpenAPITypes(spec *apiSpec) string {
+	var b strings.Builder
+	b.WriteString("// Package openapi holds the types and handler stubs of docs/openapi.yaml.\npackage openapi\n")
+	for _, def := range spec.Types {
+		if def.Alias != nil {
+			fmt.Fprintf(&b, "\ntype %s = %s\n", def.Name, goAPIType(*def.Alias))
+			continue
+		}
+		fmt.Fprintf(&b, "\ntype %s struct {\n", def.Name)
+		seen := map[string]bool{}
+		for _, f := range def.Fields {
+			name := typeIdent(f.Name)
+			for i := 2; seen[name]; i++ {
+				name = fmt.Sprintf("%s%d", typeIdent(f.Name), i)
+			}
+			seen[name] = true
+			tag := f.Name
+			if !f.Required {
+				tag += ",omitempty"
+			}
+			typ := goAPIType(f.Type)
+			// Struct fields hold other structs by pointer, which also
+			// allows self-references.
+			if def := spec.typeDef(f.Type.Ref); f.Type.Kind == apiRef && def != nil && def.Alias == nil {
+				typ = "*" + typ
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", name, typ, tag)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func goOpenAPIHandlers(spec *apiSpec, framework string) string {
+	routes := colonPaths(spec.Operations)
+	var b strings.Builder
+	b.WriteString("package openapi\n\nimport (\n")
+	if framework == "gin" {
+		b.WriteString("\t\"net/http\"\n\n\t\"github.com/gin-gonic/gin\"\n")
+	} else {
+		b.WriteString("\t\"github.com/gofiber/fiber/v2\"\n")
+	}
+	b.WriteString(")\n\n// Handler serves the operations of docs/openapi.yaml. Every method is a\n// stub answering 501 Not Implemented until it is filled in.\ntype Handler struct{}\n\nfunc NewHandler() *Handler { return &Handler{} }\n\n")
+	if framework == "gin" {
+		b.WriteString("// Register mounts the operations on r.\nfunc (h *Handler) Register(r *gin.Engine) {\n")
+		for i, op := range spec.Operations {
+			fmt.Fprintf(&b, "\tr.%s(%q, h.%s)\n", strings.ToUpper(op.Method), routes[i], op.Name)
+		}
+	} else {
+		b.WriteString("// Register mounts the operations on app.\nfunc (h *Handler) Register(app *fiber.App) {\n")
+		for i, op := range spec.Operations {
+			fmt.Fprintf(&b, "\tapp.%s(%q, h.%s)\n", toPascal(op.Method), routes[i], op.Name)
+		}
+	}
+	b.WriteString("}\n")
+	for i, op := range spec.Operations {
+		fmt.Fprintf(&b, "\n// %s handles %s\n", op.Name, stubDoc(op, routes[i]))
+		if framework == "gin" {
+			fmt.Fprintf(&b, "func (h *Handler) %s(c *gin.Context) {\n", op.Name)
+			if op.Request != "" {
+				fmt.Fprintf(&b, "\tvar req %s\n\tif err := c.ShouldBindJSON(&req); err != nil {\n\t\tc.JSON(http.StatusBadRequest, gin.H{\"error\": err.Error()})\n\t\treturn\n\t}\n", op.Request)
+			}
+			b.WriteString("\tnotImplemented(c)\n}\n")
+			continue
+		}
+		fmt.Fprintf(&b, "func (h *Handler) %s(c *fiber.Ctx) error {\n", op.Name)
+		if op.Request != "" {
+			fmt.Fprintf(&b, "\tvar req %s\n\tif err := c.BodyParser(&req); err != nil {\n\t\treturn c.Status(fiber.StatusBadRequest).JSON(fiber.Map{\"error\": err.Error()})\n\t}\n", op.Request)
+		}
+		b.WriteString("\treturn notImplemented(c)\n}\n")
+	}
+	if framework == "gin" {
+		b.WriteString("\nfunc notImplemented(c *gin.Context) {\n\tc.JSON(http.StatusNotImplemented, gin.H{\"error\": \"not implemented\"})\n}\n")
+	} else {
+		b.WriteString("\nfunc notImplemented(c *fiber.Ctx) error {\n\treturn c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{\"error\": \"not implemented\"})\n}\n")
+	}
+	return b.String()
+}
+
+func jsAPIType(t apiType) string {
+	switch t.Kind {
+	case kindInt, kindFloat:
+		return "number"
+	case kindBool:
+		return "boolean"
+	case kindString:
+		return "string"
+	case apiArray:
+		return jsAPIType(*t.Items) + "[]"
+	case apiObject:
+		return "Object<string, *>"
+	case apiRef:
+		return t.Ref
+	}
+	return "*"
+}
+
+func nodeOpenAPITypes(spec *apiSpec) string {
+	var b strings.Builder
+	b.WriteString("// JSDoc types of the schemas and bodies in docs/openapi.yaml.\n")
+	for _, def := range spec.Types {
+		if def.Alias != nil {
+			fmt.Fprintf(&b, "\n/** @typedef {%s} %s */\n", jsAPIType(*def.Alias), def.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "\n/**\n * @typedef {object} %s\n", def.Name)
+		for _, f := range def.Fields {
+			name := f.Name
+			if !f.Required {
+				name = "[" + name + "]"
+			}
+			fmt.Fprintf(&b, " * @property {%s} %s\n", jsAPIType(f.Type), name)
+		}
+		b.WriteString(" */\n")
+	}
+	b.WriteString("\nexport {};\n")
+	return b.String()
+}
+
+// jsRequestType spells the request a handler receives: path and query
+// parameters arrive as strings, the body as its schema type.
+func jsRequestType(op apiOperation) string {
+	var parts []string
+	for _, in := range []string{"path", "query"} {
+		var props []string
+		for _, p := range op.Params {
+			if p.In != in {
+				continue
+			}
+			name := p.Name
+			if !jsIdentRegex.MatchString(name) {
+				name = "'" + name + "'"
+			}
+			if !p.Required {
+				name += "?"
+			}
+			props = append(props, name+": string")
+		}
+		if len(props) > 0 {
+			key := map[string]string{"path": "params", "query": "query"}[in]
+			parts = append(parts, key+": { "+strings.Join(props, ", ")+" }")
+		}
+	}
+	if op.Request != "" {
+		parts = append(parts, "body: import('./types.js')."+op.Request)
+	}
+	if len(parts) == 0 {
+		return "object"
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+func nodeOpenAPIRoutes(spec *apiSpec, framework string) string {
+	routes := colonPaths(spec.Operations)
+	var b strings.Builder
+	b.WriteString("// Handler stubs of docs/openapi.yaml; each answers 501 until it is implemented.\n\nconst notImplemented = { error: 'not implemented' };\n")
+	names := make([]string, len(spec.Operations))
+	for i, op := range spec.Operations {
+		names[i] = camelName(op.Name)
+		if slices.Contains(jsReserved, names[i]) {
+			names[i] += "Handler"
+		}
+		fmt.Fprintf(&b, "\n/**\n * %s handles %s\n", names[i], stubDoc(op, routes[i]))
+		if framework == "express" {
+			fmt.Fprintf(&b, " * @param {%s} req\n */\nexport function %s(req, res) {\n  res.status(501).json(notImplemented);\n}\n", jsRequestType(op), names[i])
+			continue
+		}
+		fmt.Fprintf(&b, " * @param {%s} request\n */\nexport async function %s(request, reply) {\n  return reply.code(501).send(notImplemented);\n}\n", jsRequestType(op), names[i])
+	}
+	b.WriteString("\nexport function registerOpenAPIRoutes(app) {\n")
+	for i, op := range spec.Operations {
+		fmt.Fprintf(&b, "  app.%s('%s', %s);\n", op.Method, routes[i], names[i])
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// pythonIdent turns a JSON name into a Python identifier, e.g. petId -> pet_id.
+func pythonIdent(name string) string {
+	id := columnName(name)
+	switch {
+	case id == "":
+		return "field"
+	case id[0] >= '0' && id[0] <= '9':
+		return "f_" + id
+	case slices.Contains(pythonKeywords, id):
+		return id + "_"
+	}
+	return id
+}
+
+func pythonAPIType(t apiType) string {
+	switch t.Kind {
+	case kindInt:
+		return "int"
+	case kindFloat:
+		return "float"
+	case kindBool:
+		return "bool"
+	case kindString:
+		return "str"
+	case apiArray:
+		return "list[" + pythonAPIType(*t.Items) + "]"
+	case apiObject:
+		return "dict[str, Any]"
+	case apiRef:
+		return t.Ref
+	}
+	return "Any"
+}
+
+// aliasOrder lists the aliases so that each follows the aliases it names;
+// Python evaluates them eagerly.
+func aliasOrder(spec *apiSpec) []apiTypeDef {
+	var pending, out []apiTypeDef
+	for _, def := range spec.Types {
+		if def.Alias != nil {
+			pending = append(pending, def)
+		}
+	}
+	done := map[string]bool{}
+	for len(pending) > 0 {
+		next := slices.IndexFunc(pending, func(def apiTypeDef) bool {
+			t := *def.Alias
+			for t.Kind == apiArray {
+				t = *t.Items
+			}
+			dep := spec.typeDef(t.Ref)
+			return t.Kind != apiRef || dep == nil || dep.Alias == nil || done[t.Ref]
+		})
+		if next < 0 {
+			next = 0
+		}
+		done[pending[next].Name] = true
+		out = append(out, pending[next])
+		pending = slices.Delete(pending, next, next+1)
+	}
+	return out
+}
+
+func pythonOpenAPISchemas(spec *apiSpec) string {
+	var b strings.Builder
+	var classes []string
+	for _, def := range spec.Types {
+		if def.Alias != nil {
+			continue
+		}
+		classes = append(classes, def.Name)
+		fmt.Fprintf(&b, "\n\nclass %s(BaseModel):\n", def.Name)
+		if len(def.Fields) == 0 {
+			b.WriteString("    model_config = ConfigDict(extra='allow')\n")
+			continue
+		}
+		b.WriteString("    model_config = ConfigDict(populate_by_name=True)\n\n")
+		seen := map[string]bool{}
+		for _, f := range def.Fields {
+			name := pythonIdent(f.Name)
+			for i := 2; seen[name]; i++ {
+				name = fmt.Sprintf("%s_%d", pythonIdent(f.Name), i)
+			}
+			seen[name] = true
+			typ := pythonAPIType(f.Type)
+			var args []string
+			if !f.Required {
+				typ += " | None"
+				args = append(args, "default=None")
+			}
+			if name != f.Name {
+				args = append(args, "alias='"+f.Name+"'")
+			}
+			switch {
+			case len(args) == 0:
+				fmt.Fprintf(&b, "    %s: %s\n", name, typ)
+			case len(args) == 1 && args[0] == "default=None":
+				fmt.Fprintf(&b, "    %s: %s = None\n", name, typ)
+			default:
+				fmt.Fprintf(&b, "    %s: %s = Field(%s)\n", name, typ, strings.Join(args, ", "))
+			}
+		}
+	}
+	if aliases := aliasOrder(spec); len(aliases) > 0 {
+		b.WriteString("\n\n")
+		for _, def := range aliases {
+			fmt.Fprintf(&b, "%s = %s\n", def.Name, pythonAPIType(*def.Alias))
+		}
+	}
+	if len(classes) > 0 {
+		b.WriteString("\n")
+		for _, name := range classes {
+			fmt.Fprintf(&b, "%s.model_rebuild()\n", name)
+		}
+	}
+	head := "from __future__ import annotations\n"
+	if regexp.MustCompile(`\bAny\b`).MatchString(b.String()) {
+		head += "\nfrom typing import Any\n"
+	}
+	if len(classes) > 0 {
+		head += "\nfrom pydantic import BaseModel, ConfigDict"
+		if strings.Contains(b.String(), "Field(") {
+			head += ", Field"
+		}
+		head += "\n"
+	}
+	return head + b.String()
+}
+
+// snakePath renames path parameters to Python identifiers, e.g.
+// /pets/{petId} -> /pets/{pet_id}.
+func snakePath(p string, format string) string {
+	return pathParamRegex.ReplaceAllStringFunc(p, func(m string) string {
+		return fmt.Sprintf(format, pythonIdent(m[1:len(m)-1]))
+	})
+}
+
+func pythonOpenAPIRoutes(spec *apiSpec) string {
+	var used []string
+	var b strings.Builder
+	needQuery := false
+	for _, op := range spec.Operations {
+		fmt.Fprintf(&b, "\n\n@openapi_router.%s('%s'", op.Method, snakePath(op.Path, "{%s}"))
+		if op.Status != 200 {
+			fmt.Fprintf(&b, ", status_code=%d", op.Status)
+		}
+		if op.Response != "" {
+			fmt.Fprintf(&b, ", response_model=%s", op.Response)
+			used = append(used, op.Response)
+		}
+		var required, optional []string
+		taken := map[string]bool{}
+		for _, p := range op.Params {
+			name := pythonIdent(p.Name)
+			taken[name] = true
+			typ := pythonAPIType(p.Type)
+			switch {
+			case p.In == "path":
+				required = append(required, name+": "+typ)
+			case name != p.Name:
+				needQuery = true
+				if p.Required {
+					required = append(required, fmt.Sprintf("%s: %s = Query(alias='%s')", name, typ, p.Name))
+				} else {
+					optional = append(optional, fmt.Sprintf("%s: %s | None = Query(default=None, alias='%s')", name, typ, p.Name))
+				}
+			case p.Required:
+				required = append(required, name+": "+typ)
+			default:
+				optional = append(optional, name+": "+typ+" | None = None")
+			}
+		}
+		if op.Request != "" {
+			body := "body"
+			if taken[body] {
+				body = "payload"
+			}
+			required = append(required, body+": "+op.Request)
+			used = append(used, op.Request)
+		}
+		fmt.Fprintf(&b, ")\nasync def %s(%s):\n    \"\"\"%s\"\"\"\n    raise HTTPException(status_code=501, detail='not implemented')\n",
+			snakeCase(op.Name), strings.Join(slices.Concat(required, optional), ", "), strings.ReplaceAll(stubDoc(op, op.Path), `"`, `'`))
+	}
+	head := "from fastapi import APIRouter, HTTPException"
+	if needQuery {
+		head += ", Query"
+	}
+	head += "\n"
+	if len(used) > 0 {
+		slices.Sort(used)
+		head += "\nfrom app.openapi.schemas import " + strings.Join(slices.Compact(used), ", ") + "\n"
+	}
+	return head + "\nopenapi_router = APIRouter()\n" + b.String()
+}
+
+// djangoAPIField spells t as a serializer field; serializers not yet defined
+// fall back to JSONField.
+func djangoAPIField(spec *apiSpec, t apiType, args string, defined map[string]bool) string {
+	t = spec.resolve(t)
+	switch t.Kind {
+	case kindInt:
+		return "serializers.IntegerField(" + args + ")"
+	case kindFloat:
+		return "serializers.FloatField(" + args + ")"
+	case kindBool:
+		return "serializers.BooleanField(" + args + ")"
+	case kindString:
+		return "serializers.CharField(" + args + ")"
+	case apiObject:
+		return "serializers.DictField(" + args + ")"
+	case apiRef:
+		if defined[t.Ref] {
+			return t.Ref + "Serializer(" + args + ")"
+		}
+	case apiArray:
+		if item := spec.resolve(*t.Items); item.Kind == apiRef && defined[item.Ref] {
+			return item.Ref + "Serializer(" + strings.TrimPrefix(args+", many=True", ", ") + ")"
+		}
+		return "serializers.ListField(" + strings.TrimPrefix(args+", child="+djangoAPIField(spec, *t.Items, "", defined), ", ") + ")"
+	}
+	return "serializers.JSONField(" + args + ")"
+}
+
+func djangoOpenAPISerializers(spec *apiSpec) string {
+	var structs []apiTypeDef
+	for _, def := range spec.Types {
+		if def.Alias == nil {
+			structs = append(structs, def)
+		}
+	}
+	deps := func(def apiTypeDef) []string {
+		var out []string
+		for _, f := range def.Fields {
+			t := spec.resolve(f.Type)
+			for t.Kind == apiArray {
+				t = spec.resolve(*t.Items)
+			}
+			if t.Kind == apiRef && t.Ref != def.Name {
+				out = append(out, t.Ref)
+			}
+		}
+		return out
+	}
+	var b strings.Builder
+	b.WriteString("from rest_framework import serializers\n")
+	defined := map[string]bool{}
+	for len(structs) > 0 {
+		next := slices.IndexFunc(structs, func(def apiTypeDef) bool {
+			return !slices.ContainsFunc(deps(def), func(d string) bool { return !defined[d] })
+		})
+		if next < 0 {
+			next = 0
+		}
+		def := structs[next]
+		structs = slices.Delete(structs, next, next+1)
+		fmt.Fprintf(&b, "\n\nclass %sSerializer(serializers.Serializer):\n", def.Name)
+		var lines []string
+		for _, f := range def.Fields {
+			if !sqlIdentRegex.MatchString(f.Name) || slices.Contains(pythonKeywords, f.Name) {
+				continue
+			}
+			args := ""
+			if !f.Required {
+				args = "required=False, allow_null=True"
+			}
+			lines = append(lines, fmt.Sprintf("    %s = %s\n", f.Name, djangoAPIField(spec, f.Type, args, defined)))
+		}
+		if len(lines) == 0 {
+			lines = []string{"    pass\n"}
+		}
+		b.WriteString(strings.Join(lines, ""))
+		defined[def.Name] = true
+	}
+	return b.String()
+}
+
+// djangoOpenAPIViews renders one api_view per path, dispatching on the
+// method, and the urls that route the spec paths as written.
+func djangoOpenAPIViews(spec *apiSpec) (string, string) {
+	type view struct {
+		name   string
+		route  string
+		params []string
+		ops    []apiOperation
+	}
+	var views []*view
+	byPath := map[string]*view{}
+	for _, op := range spec.Operations {
+		v := byPath[op.Path]
+		if v == nil {
+			name := pythonIdent(strings.NewReplacer("{", "", "}", "", "/", "_").Replace(strings.Trim(op.Path, "/")))
+			if strings.Trim(op.Path, "/") == "" {
+				name = "root"
+			}
+			name = strings.TrimSuffix(name, "_") + "_view"
+			for i := 2; slices.ContainsFunc(views, func(o *view) bool { return o.name == name }); i++ {
+				name = fmt.Sprintf("%s_%d", name, i)
+			}
+			v = &view{name: name, route: strings.TrimPrefix(op.Path, "/")}
+			for _, m := range pathParamRegex.FindAllStringSubmatch(op.Path, -1) {
+				v.params = append(v.params, pythonIdent(m[1]))
+			}
+			v.route = pathParamRegex.ReplaceAllStringFunc(v.route, func(m string) string {
+				conv := "str"
+				for _, p := range op.Params {
+					if p.In == "path" && p.Name == m[1:len(m)-1] && p.Type.Kind == kindInt {
+						conv = "int"
+					}
+				}
+				return "<" + conv + ":" + pythonIdent(m[1:len(m)-1]) + ">"
+			})
+			byPath[op.Path] = v
+			views = append(views, v)
+		}
+		v.ops = append(v.ops, op)
+	}
+	var used []string
+	var b strings.Builder
+	for _, v := range views {
+		var methods, docs []string
+		var checks strings.Builder
+		for _, op := range v.ops {
+			methods = append(methods, "'"+strings.ToUpper(op.Method)+"'")
+			docs = append(docs, op.Name+" handles "+strings.ReplaceAll(stubDoc(op, op.Path), `"`, `'`))
+			if op.Request == "" {
+				continue
+			}
+			t := spec.resolve(apiType{Kind: apiRef, Ref: op.Request})
+			many := ""
+			if t.Kind == apiArray {
+				t, many = spec.resolve(*t.Items), ", many=True"
+			}
+			if t.Kind != apiRef {
+				continue
+			}
+			used = append(used, t.Ref+"Serializer")
+			fmt.Fprintf(&checks, "    if request.method == '%s':\n        %sSerializer(data=request.data%s).is_valid(raise_exception=True)\n", strings.ToUpper(op.Method), t.Ref, many)
+		}
+		args := slices.Concat([]string{"request"}, v.params)
+		fmt.Fprintf(&b, "\n\n@api_view([%s])\ndef %s(%s):\n    \"\"\"%s\"\"\"\n%s    return Response(NOT_IMPLEMENTED, status=status.HTTP_501_NOT_IMPLEMENTED)\n",
+			strings.Join(methods, ", "), v.name, strings.Join(args, ", "), strings.Join(docs, "\n\n    "), checks.String())
+	}
+	head := "from rest_framework import status\nfrom rest_framework.decorators import api_view\nfrom rest_framework.response import Response\n"
+	if len(used) > 0 {
+		slices.Sort(used)
+		head += "\nfrom .openapi_serializers import " + strings.Join(slices.Compact(used), ", ") + "\n"
+	}
+
+	var urls strings.Builder
+	urls.WriteString("from django.urls import path\n\nfrom . import openapi_views\n\nurlpatterns = [\n")
+	for _, v := range views {
+		fmt.Fprintf(&urls, "    path('%s', openapi_views.%s),\n", v.route, v.name)
+	}
+	urls.WriteString("]\n")
+	return head + "\nNOT_IMPLEMENTED = {'error': 'not implemented'}\n" + b.String(), urls.String()
+}
@@ -0,0 +1,293 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const petstoreSpec = `openapi: 3.0.3
+info: {title: Petstore, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List all pets
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+      responses:
+        '200':
+          description: A page of pets
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    post:
+      operationId: createPet
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+      responses:
+        201:
+          $ref: '#/components/responses/PetResponse'
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    delete:
+      operationId: deletePet
+      responses:
+        '204': {description: Deleted}
+  /pets/{id}/photos:
+    post:
+      operationId: uploadPhoto
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [url]
+              properties:
+                url: {type: string}
+      responses:
+        '202': {description: Accepted}
+  /health:
+    get:
+      responses:
+        '200': {description: OK}
+  /api/v1/tags/{id}:
+    get:
+      responses:
+        '200': {description: OK}
+components:
+  parameters:
+    PetId: {name: petId, in: path, required: true, schema: {type: integer}}
+  responses:
+    PetResponse:
+      description: A pet
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Pet'}
+  schemas:
+    NewPet:
+      type: object
+      x-stacksprint-model: false
+      required: [name]
+      properties:
+        name: {type: string, maxLength: 80}
+        status: {$ref: '#/components/schemas/Status'}
+    Pet:
+      allOf:
+        - $ref: '#/components/schemas/NewPet'
+        - type: object
+          required: [id]
+          properties:
+            id: {type: integer, format: int64}
+            bornAt: {type: string, format: date-time}
+            owner: {$ref: '#/components/schemas/Owner'}
+            tags: {type: array, items: {$ref: '#/components/schemas/Tag'}}
+            weight: {type: number, default: heavy}
+            nicknames: {type: array, items: {type: string}}
+    Owner:
+      type: object
+      properties:
+        email: {type: string, nullable: true}
+        pets: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    Tag:
+      type: object
+      properties:
+        label: {type: string}
+        pets: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+    Status:
+      type: string
+      enum: [available, sold]
+      default: available
+`
+
+func TestParseOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	models, warnings, err := ParseOpenAPI(petstoreSpec)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []DataModel{
+		{Name: "pet", Fields: []DataField{
+			{Name: "name", Type: "string", Required: true, MaxLength: 80},
+			{Name: "status", Type: "string", Default: "available", Enum: []string{"available", "sold"}},
+			{Name: "born_at", Type: "datetime"},
+			{Name: "weight", Type: "float"},
+		}, Relations: []DataRelation{
+			{Kind: RelationBelongsTo, Model: "owner", ForeignKey: "owner_id"},
+			{Kind: RelationManyToMany, Model: "tag"},
+		}},
+		{Name: "owner", Fields: []DataField{{Name: "email", Type: "string"}}, Relations: []DataRelation{
+			{Kind: RelationHasMany, Model: "pet", ForeignKey: "owner_id"},
+		}},
+		{Name: "tag", Fields: []DataField{{Name: "label", Type: "string"}}, Relations: []DataRelation{
+			{Kind: RelationManyToMany, Model: "pet"},
+		}},
+	}
+	if !reflect.DeepEqual(models, want) {
+		t.Fatalf("models:\n got %+v\nwant %+v", models, want)
+	}
+	for _, w := range []string{
+		`Property Pet.weight default was dropped because "heavy" is not a number.`,
+		"Property Pet.nicknames is an array and was not imported as a column.",
+	} {
+		if !strings.Contains(strings.Join(warnings, "\n"), w) {
+			t.Fatalf("expected warning %q, got %q", w, warnings)
+		}
+	}
+}
+
+func TestParseOpenAPIErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"swagger: '2.0'\npaths: {}":      "swagger 2.0 documents are not supported",
+		"info: {title: x}":               `expected an OpenAPI 3 document, got openapi ""`,
+		"openapi: 3.1.0\ninfo: {}":       "the document defines no paths and no components/schemas",
+		"openapi: 3.0.0\npaths: [1, 2]":  "line 2: expected a mapping of paths",
+		"openapi: 3.0.0\npaths: {/a: {}": "did not find expected",
+	}
+	for doc, want := range cases {
+		if _, _, err := ParseOpenAPI(doc); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("ParseOpenAPI(%q): expected error containing %q, got %v", doc, want, err)
+		}
+	}
+}
+
+func TestBuildRendersOpenAPIStubs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		req   GenerateRequest
+		files map[string][]string
+	}{
+		{
+			name: "go gin",
+			req:  GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql"},
+			files: map[string][]string{
+				"internal/openapi/types.go": {
+					"type Pet struct {\n\tName string `json:\"name\"`\n\tStatus Status `json:\"status,omitempty\"`\n\tId int `json:\"id\"`",
+					"Owner *Owner `json:\"owner,omitempty\"`", "type Status = string", "type ListPetsResponse = []Pet", "type UploadPhotoRequest struct",
+				},
+				"internal/openapi/handlers.go": {
+					`r.GET("/pets", h.ListPets)`, `r.DELETE("/pets/:petId", h.DeletePet)`, `r.POST("/pets/:petId/photos", h.UploadPhoto)`,
+					"// ListPets handles GET /pets: List all pets. Query: limit. Responds 200 with ListPetsResponse.",
+					"var req NewPet\n\tif err := c.ShouldBindJSON(&req)",
+				},
+				"cmd/server/main.go": {"stacksprint-generated/internal/openapi\"\n)", "openapi.NewHandler().Register(r)"},
+			},
+		},
+		{
+			name: "go fiber",
+			req:  GenerateRequest{Language: "go", Framework: "fiber", Architecture: "mvp"},
+			files: map[string][]string{
+				"internal/openapi/handlers.go": {`app.Delete("/pets/:petId", h.DeletePet)`, "if err := c.BodyParser(&req); err != nil"},
+				"cmd/server/main.go":           {"openapi.NewHandler().Register(app)"},
+			},
+		},
+		{
+			name: "node fastify",
+			req:  GenerateRequest{Language: "node", Framework: "fastify", Architecture: "hexagonal"},
+			files: map[string][]string{
+				"src/openapi/types.js":  {" * @typedef {object} Pet\n * @property {string} name\n", "/** @typedef {Pet[]} ListPetsResponse */"},
+				"src/openapi/routes.js": {"app.delete('/pets/:petId', deletePet);", " * @param {{ body: import('./types.js').NewPet }} request\n */\nexport async function createPet(request, reply) {"},
+				"src/index.js":          {"import { registerOpenAPIRoutes } from './openapi/routes.js';", "registerOpenAPIRoutes(app);"},
+			},
+		},
+		{
+			name: "fastapi",
+			req:  GenerateRequest{Language: "python", Framework: "fastapi", Architecture: "mvp"},
+			files: map[string][]string{
+				"app/openapi/schemas.py": {"class Pet(BaseModel):", "    born_at: str | None = Field(default=None, alias='bornAt')", "ListPetsResponse = list[Pet]", "Pet.model_rebuild()"},
+				"app/openapi/routes.py": {
+					"@openapi_router.post('/pets', status_code=201, response_model=Pet)\nasync def create_pet(body: NewPet):",
+					"async def delete_pet(pet_id: int):", "async def upload_photo(id: str, body: UploadPhotoRequest):",
+				},
+				"app/main.py": {"from app.openapi.routes import openapi_router", "app.include_router(openapi_router)"},
+			},
+		},
+		{
+			name: "django",
+			req:  GenerateRequest{Language: "python", Framework: "django", Architecture: "mvp", Database: "postgresql"},
+			files: map[string][]string{
+				"api/openapi_serializers.py": {"class PetSerializer(serializers.Serializer):", "class TagSerializer(serializers.Serializer):\n    label = serializers.CharField(required=False, allow_null=True)\n    pets = PetSerializer(required=False, allow_null=True, many=True)"},
+				"api/openapi_views.py":       {"@api_view(['GET', 'POST'])\ndef pets_view(request):", "NewPetSerializer(data=request.data).is_valid(raise_exception=True)"},
+				"api/openapi_urls.py":        {"path('pets/<int:pet_id>', openapi_views.pets_pet_id_view),", "path('health', openapi_views.health_view),"},
+				"config/urls.py":             {"path('', include('api.openapi_urls'))"},
+			},
+		},
+	}
+	engine := testEngine(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.req.Custom.OpenAPI = petstoreSpec
+			project, err := engine.Build(context.Background(), tc.req)
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			for path, wants := range tc.files {
+				body, ok := project.Tree.Files[path]
+				if !ok {
+					t.Fatalf("expected %s to be generated", path)
+				}
+				for _, want := range wants {
+					if !strings.Contains(body, want) {
+						t.Fatalf("expected %s to contain %q, got:\n%s", path, want, body)
+					}
+				}
+			}
+			if got := project.Tree.Files["docs/openapi.yaml"]; got != petstoreSpec {
+				t.Fatalf("expected docs/openapi.yaml to be the input document, got:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestBuildImportsOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	req := GenerateRequest{Language: "node", Framework: "express", Architecture: "mvp", Database: "postgresql", Custom: CustomOptions{
+		OpenAPI: petstoreSpec,
+		Models:  []DataModel{{Name: "owner", Fields: []DataField{{Name: "phone", Type: "string"}}}},
+	}}
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	for _, path := range []string{"src/routes/petRoutes.js", "src/routes/tagRoutes.js", "src/routes/ownerRoutes.js"} {
+		if _, ok := project.Tree.Files[path]; !ok {
+			t.Fatalf("expected %s to be generated", path)
+		}
+	}
+	if strings.Contains(project.Tree.Files["src/models/owner.js"], "email") {
+		t.Fatalf("expected the declared owner model to win over the schema, got:\n%s", project.Tree.Files["src/models/owner.js"])
+	}
+	warnings := strings.Join(project.Warnings(), "\n")
+	for _, want := range []string{
+		"custom.openapi: Schema Owner was skipped because custom.models already declares Owner.",
+		"custom.openapi: Operation GET /health is served by the generated health check; no stub was generated.",
+		"custom.openapi: Operation GET /api/v1/tags/{id} is served by the generated Tag CRUD routes; no stub was generated.",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Fatalf("expected warning %q, got:\n%s", want, warnings)
+		}
+	}
+	if routes := project.Tree.Files["src/openapi/routes.js"]; strings.Contains(routes, "/health") || strings.Contains(routes, "/api/v1/tags") {
+		t.Fatalf("expected reserved routes to be skipped, got:\n%s", routes)
+	}
+
+	req.Custom.OpenAPI = "swagger: '2.0'"
+	_, err = engine.Build(context.Background(), req)
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/custom/openapi" {
+		t.Fatalf("expected a /custom/openapi validation error, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	DiffAdded     = "added"
+	DiffModified  = "modified"
+	DiffUnchanged = "unchanged"
+
+	diffContextLines    = 3
+	maxDiffArchiveBytes = 64 << 20
+)
+
+// DiffRequest describes an existing project to compare generated output
+// against. The project is given as at most one of: a base64 zip/tar.gz
+// Archive, a Files map (path -> content) or a Hashes map (path -> SHA-256).
+// With Hashes, modified files are reported without a diff body.
+type DiffRequest struct {
+	Request GenerateRequest   `json:"request"`
+	Archive string            `json:"archive,omitempty"`
+	Files   map[string]string `json:"files,omitempty"`
+	Hashes  map[string]string `json:"hashes,omitempty"`
+}
+
+type FileDiff struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Diff   string `json:"diff,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type DiffSummary struct {
+	Added     int `json:"added"`
+	Modified  int `json:"modified"`
+	Unchanged int `json:"unchanged"`
+}
+
+type DiffResult struct {
+	Files    []FileDiff  `json:"files"`
+	Summary  DiffSummary `json:"summary"`
+	Warnings []string    `json:"warnings"`
+}
+
+// Diff is a dry run: it renders the request and compares every generated file
+// with the uploaded project without writing anything. Files that only exist
+// in the project are left out, since generation never touches them.
+func (e *Engine) Diff(ctx context.Context, dr DiffRequest) (DiffResult, error) {
+	project, err := e.Build(ctx, dr.Request)
+	if err != nil {
+		return DiffResult{}, prefixValidationPaths(err, "/request")
+	}
+	current, err := diffSource(dr, ArchiveRoot(project.Request))
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	tree := cloneTree(project.Tree)
+	ensureGitKeepFiles(&tree)
+	result := DiffResult{Files: make([]FileDiff, 0, len(tree.Files)), Warnings: project.Warnings()}
+	for _, p := range fileNamesSorted(tree.Files) {
+		var file FileDiff
+		if dr.Hashes != nil {
+			file = diffByHash(p, tree.Files[p], dr.Hashes)
+		} else {
+			file = diffByContent(p, tree.Files[p], current)
+		}
+		switch file.Status {
+		case DiffAdded:
+			result.Summary.Added++
+		case DiffModified:
+			result.Summary.Modified++
+		default:
+			result.Summary.Unchanged++
+		}
+		result.Files = append(result.Files, file)
+	}
+	return result, nil
+}
+
+func diffSource(dr DiffRequest, root string) (map[string]string, error) {
+	sources := 0
+	for _, set := range []bool{dr.Archive != "", dr.Files != nil, dr.Hashes != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, &ValidationError{Errors: []FieldError{{
+			Code:    CodeInvalidFormat,
+			Path:    "",
+			Message: "send the current project as one of archive, files or hashes",
+			Hint:    "Drop all but one of archive, files and hashes.",
+		}}}
+	}
+	if dr.Archive == "" {
+		return dr.Files, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(dr.Archive))
+	if err != nil {
+		return nil, projectArchiveError(fmt.Errorf("not valid base64: %w", err))
+	}
+	files, err := ReadArchive(raw, maxDiffArchiveBytes, nil)
+	if err != nil {
+		return nil, projectArchiveError(err)
+	}
+	// Archives of a new project usually wrap everything in root.name/.
+	if prefix := commonTopDir(files); root != "" && prefix == root+"/" {
+		stripped := make(map[string]string, len(files))
+		for p, content := range files {
+			stripped[strings.TrimPrefix(p, prefix)] = content
+		}
+		files = stripped
+	}
+	return files, nil
+}
+
+func projectArchiveError(err error) error {
+	return &ValidationError{Errors: []FieldError{{
+		Code:    CodeInvalidFormat,
+		Path:    "/archive",
+		Message: fmt.Sprintf("invalid project archive: %v", err),
+		Hint:    "Send the project as a base64-encoded .zip or .tar.gz.",
+	}}}
+}
+
+func diffByContent(p, next string, current map[string]string) FileDiff {
+	cur, ok := current[p]
+	switch {
+	case !ok:
+		return FileDiff{Path: p, Status: DiffAdded, Diff: unifiedDiff("/dev/null", "b/"+p, "", next, diffContextLines)}
+	case cur == next:
+		return FileDiff{Path: p, Status: DiffUnchanged}
+	case strings.ContainsRune(cur, 0):
+		return FileDiff{Path: p, Status: DiffModified, Diff: fmt.Sprintf("Binary files a/%s and b/%s differ\n", p, p)}
+	default:
+		return FileDiff{Path: p, Status: DiffModified, Diff: unifiedDiff("a/"+p, "b/"+p, cur, next, diffContextLines)}
+	}
+}
+
+func diffByHash(p, next string, hashes map[string]string) FileDiff {
+	hash, ok := hashes[p]
+	switch {
+	case !ok:
+		return FileDiff{Path: p, Status: DiffAdded, Diff: unifiedDiff("/dev/null", "b/"+p, "", next, diffContextLines)}
+	case strings.EqualFold(strings.TrimPrefix(hash, "sha256:"), contentHash(next)):
+		return FileDiff{Path: p, Status: DiffUnchanged}
+	default:
+		return FileDiff{Path: p, Status: DiffModified, Reason: "content differs; send files or archive for a line diff"}
+	}
+}
+
+// prefixValidationPaths re-roots the JSON pointers of a ValidationError for
+// requests that nest the GenerateRequest under another key.
+func prefixValidationPaths(err error, prefix string) error {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		for i := range verr.Errors {
+			verr.Errors[i].Path = prefix + verr.Errors[i].Path
+		}
+	}
+	return err
+}
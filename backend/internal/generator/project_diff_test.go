@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	newText := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+	want := "--- a/f\n+++ b/f\n" +
+		"@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n\\ No newline at end of file\n"
+	if got := unifiedDiff("a/f", "b/f", oldText, newText, 3); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := unifiedDiff("/dev/null", "b/f", "", "x\n", 3); got != "--- /dev/null\n+++ b/f\n@@ -0,0 +1,1 @@\n+x\n" {
+		t.Fatalf("unexpected added-file diff:\n%s", got)
+	}
+	if got := unifiedDiff("a/f", "b/f", oldText, oldText, 3); got != "" {
+		t.Fatalf("expected empty diff for identical input, got %q", got)
+	}
+}
+
+func TestDiffAgainstUploadedProject(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	req := GenerateRequest{
+		Language:     "node",
+		Framework:    "express",
+		Architecture: "mvp",
+		Database:     "none",
+		Root:         RootOptions{Mode: "new", Name: "diff-demo"},
+	}
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	existing := cloneTree(project.Tree)
+	existing.Files["package.json"] = "{\"name\": \"ours\"}\n"
+	delete(existing.Files, "Dockerfile")
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, ArchiveZip, req, existing); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	result, err := engine.Diff(context.Background(), DiffRequest{Request: req, Archive: base64.StdEncoding.EncodeToString(buf.Bytes())})
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	byPath := map[string]FileDiff{}
+	for _, f := range result.Files {
+		byPath[f.Path] = f
+	}
+	if f := byPath["package.json"]; f.Status != DiffModified || f.Diff == "" {
+		t.Fatalf("expected package.json to be modified with a diff, got %+v", f)
+	}
+	if f := byPath["Dockerfile"]; f.Status != DiffAdded {
+		t.Fatalf("expected Dockerfile to be added, got %+v", f)
+	}
+	if f := byPath["src/index.js"]; f.Status != DiffUnchanged || f.Diff != "" {
+		t.Fatalf("expected src/index.js unchanged, got %+v", f)
+	}
+	if result.Summary.Modified != 1 || result.Summary.Added != 1 {
+		t.Fatalf("unexpected summary %+v", result.Summary)
+	}
+
+	hashes := map[string]string{"package.json": contentHash(project.Tree.Files["package.json"]), "src/index.js": "0000"}
+	result, err = engine.Diff(context.Background(), DiffRequest{Request: req, Hashes: hashes})
+	if err != nil {
+		t.Fatalf("diff by hash: %v", err)
+	}
+	for _, f := range result.Files {
+		if f.Path == "package.json" && f.Status != DiffUnchanged {
+			t.Fatalf("expected matching hash to be unchanged, got %+v", f)
+		}
+		if f.Path == "src/index.js" && (f.Status != DiffModified || f.Reason == "") {
+			t.Fatalf("expected mismatched hash to be modified with a reason, got %+v", f)
+		}
+	}
+
+	_, err = engine.Diff(context.Background(), DiffRequest{Request: req, Archive: "bm90IGFuIGFyY2hpdmU="})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/archive" {
+		t.Fatalf("expected archive error, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Version is the generator release recorded in every project manifest.
+// Release builds override it with -ldflags "-X ...generator.Version=x.y.z".
+var Version = "2.0.0-dev"
+
+const (
+	ProjectManifestPath          = ".stacksprint.json"
+	projectManifestSchemaVersion = 1
+)
+
+// ProjectManifest is written to .stacksprint.json in every generated project.
+// It records how the project was made so it can be regenerated, upgraded or
+// checked for drift later.
+type ProjectManifest struct {
+	SchemaVersion int               `json:"schema_version"`
+	Generator     GeneratorInfo     `json:"generator"`
+	Templates     TemplateInfo      `json:"templates"`
+	Request       GenerateRequest   `json:"request"`
+	Files         map[string]string `json:"files"`
+}
+
+type GeneratorInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type TemplateInfo struct {
+	Source string `json:"source"`
+	Hash   string `json:"hash"`
+	// ArchiveHash is the sha256 of an uploaded template pack archive, which
+	// the manifest does not store.
+	ArchiveHash string `json:"archive_hash,omitempty"`
+}
+
+// buildProjectManifest describes tree as rendered from req. Files maps every
+// path to the SHA-256 of its content; the manifest does not list itself.
+// Uploaded template pack archives are left out of the request to keep the
+// file small; only their hash is recorded.
+func buildProjectManifest(req GenerateRequest, tree FileTree, registry *TemplateRegistry) ProjectManifest {
+	templates := TemplateInfo{Source: registry.Source(), Hash: registry.Hash()}
+	if req.TemplatePack.Archive != "" {
+		templates.ArchiveHash = "sha256:" + contentHash(req.TemplatePack.Archive)
+		req.TemplatePack.Archive = ""
+	}
+	files := make(map[string]string, len(tree.Files))
+	for p, content := range tree.Files {
+		if p == ProjectManifestPath {
+			continue
+		}
+		files[p] = contentHash(content)
+	}
+	return ProjectManifest{
+		SchemaVersion: projectManifestSchemaVersion,
+		Generator:     GeneratorInfo{Name: "stacksprint", Version: Version},
+		Templates:     templates,
+		Request:       req,
+		Files:         files,
+	}
+}
+
+func addProjectManifest(tree *FileTree, req GenerateRequest, registry *TemplateRegistry) error {
+	body, err := json.MarshalIndent(buildProjectManifest(req, *tree, registry), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", ProjectManifestPath, err)
+	}
+	addFile(tree, ProjectManifestPath, string(body)+"\n")
+	return nil
+}
+
+// ParseProjectManifest decodes a .stacksprint.json file.
+func ParseProjectManifest(body []byte) (ProjectManifest, error) {
+	var m ProjectManifest
+	if err := json.Unmarshal(body, &m); err != nil {
+		return ProjectManifest{}, fmt.Errorf("invalid %s: %w", ProjectManifestPath, err)
+	}
+	if m.SchemaVersion == 0 || m.SchemaVersion > projectManifestSchemaVersion {
+		return ProjectManifest{}, fmt.Errorf("unsupported %s schema_version %d", ProjectManifestPath, m.SchemaVersion)
+	}
+	return m, nil
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBuildWritesProjectManifest(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	project, err := engine.Build(context.Background(), GenerateRequest{
+		Language:     " Go ",
+		Framework:    "GIN",
+		Architecture: "mvp",
+		Database:     "mongodb",
+		UseORM:       true,
+		Root:         RootOptions{Name: "manifest-demo"},
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	body, ok := project.Tree.Files[ProjectManifestPath]
+	if !ok {
+		t.Fatalf("expected %s in the generated tree", ProjectManifestPath)
+	}
+	manifest, err := ParseProjectManifest([]byte(body))
+	if err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+
+	if manifest.Generator.Version != Version || manifest.Templates.Source != "embedded" {
+		t.Fatalf("unexpected generator/template info: %+v %+v", manifest.Generator, manifest.Templates)
+	}
+	if manifest.Templates.Hash == "" || manifest.Templates.Hash != engine.registry.Hash() {
+		t.Fatalf("expected template hash %q, got %q", engine.registry.Hash(), manifest.Templates.Hash)
+	}
+	if manifest.Request.Language != "go" || manifest.Request.Root.Mode != "new" || manifest.Request.UseORM {
+		t.Fatalf("expected normalized, rule-corrected request, got %+v", manifest.Request)
+	}
+
+	if _, ok := manifest.Files[ProjectManifestPath]; ok {
+		t.Fatalf("manifest must not hash itself")
+	}
+	if len(manifest.Files) != len(project.Tree.Files)-1 {
+		t.Fatalf("expected a hash for every other file, got %d of %d", len(manifest.Files), len(project.Tree.Files)-1)
+	}
+	for p, hash := range manifest.Files {
+		if hash != contentHash(project.Tree.Files[p]) {
+			t.Fatalf("hash mismatch for %s", p)
+		}
+	}
+}
+
+func TestProjectManifestReplacesUploadedPackWithHash(t *testing.T) {
+	t.Parallel()
+
+	req := GenerateRequest{TemplatePack: TemplatePackRef{Archive: strings.Repeat("A", 4096)}}
+	manifest := buildProjectManifest(req, FileTree{Files: map[string]string{}}, testEngine(t).registry)
+	if manifest.Request.TemplatePack.Archive != "" {
+		t.Fatalf("expected archive to be left out of the request, got %q", manifest.Request.TemplatePack.Archive[:16])
+	}
+	if manifest.Templates.ArchiveHash != "sha256:"+contentHash(req.TemplatePack.Archive) {
+		t.Fatalf("expected the archive hash in templates, got %q", manifest.Templates.ArchiveHash)
+	}
+}
+
+func TestParseProjectManifestRejectsUnknownSchema(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseProjectManifest([]byte(`{"schema_version": 99}`)); err == nil {
+		t.Fatalf("expected unsupported schema_version error")
+	}
+	if _, err := ParseProjectManifest([]byte(`not json`)); err == nil {
+		t.Fatalf("expected invalid JSON error")
+	}
+}
+
+func TestProjectManifestRebuildsImportedSchemas(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	for name, req := range map[string]GenerateRequest{
+		"openapi": {Language: "node", Framework: "express", Architecture: "mvp", Database: "postgresql", Custom: CustomOptions{OpenAPI: petstoreSpec}},
+		"ddl":     {Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql", UseORM: true, Custom: CustomOptions{DDL: postgresDDL}},
+	} {
+		project, err := engine.Build(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: build: %v", name, err)
+		}
+		manifest, err := ParseProjectManifest([]byte(project.Tree.Files[ProjectManifestPath]))
+		if err != nil {
+			t.Fatalf("%s: parse manifest: %v", name, err)
+		}
+		if len(manifest.Request.Custom.Models) != 0 {
+			t.Fatalf("%s: expected only the declared models in the manifest, got %+v", name, manifest.Request.Custom.Models)
+		}
+
+		rebuilt, err := engine.Build(context.Background(), manifest.Request)
+		if err != nil {
+			t.Fatalf("%s: rebuild: %v", name, err)
+		}
+		if warnings := strings.Join(rebuilt.Warnings(), "\n"); strings.Contains(warnings, "was skipped") {
+			t.Fatalf("%s: expected no skipped schemas on rebuild, got:\n%s", name, warnings)
+		}
+		for p, content := range project.Tree.Files {
+			if rebuilt.Tree.Files[p] != content {
+				t.Fatalf("%s: expected %s to rebuild unchanged", name, p)
+			}
+		}
+	}
+
+	req := GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql", UseORM: true, Custom: CustomOptions{DDL: postgresDDL}}
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	manifest, err := ParseProjectManifest([]byte(project.Tree.Files[ProjectManifestPath]))
+	if err != nil {
+		t.Fatalf("parse manifest: %v", err)
+	}
+	manifest.Request.Custom.DDL = strings.Replace(manifest.Request.Custom.DDL, "label varchar(40) UNIQUE", "label varchar(40) UNIQUE, color varchar(7)", 1)
+	rebuilt, err := engine.Build(context.Background(), manifest.Request)
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if models := domainSource(rebuilt.Tree); !strings.Contains(models, "Color string") {
+		t.Fatalf("expected the edited DDL to reach the rebuilt project, got:\n%s", models)
+	}
+}
@@ -6,7 +6,6 @@ import (
 )
 
 func (e *Engine) generatePythonMonolith(tree *FileTree, req GenerateRequest) error {
-	specs := pythonMonolithTemplateSpecs(req)
 	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
 	data := map[string]any{
 		"Framework":    req.Framework,
@@ -20,27 +19,20 @@ func (e *Engine) generatePythonMonolith(tree *FileTree, req GenerateRequest) err
 		"WithCRUD":     withCRUD,
 	}
 
+	if err := e.renderManifest(tree, "python/"+archTemplateName(req.Architecture), req, data, ""); err != nil {
+		return err
+	}
 	if req.Framework == "django" {
-		main, err := e.registry.Render("python/"+archTemplateName(req.Architecture)+"/main.tmpl", data)
-		if err != nil {
-			return err
-		}
-		addDjangoFiles(tree, req, main)
+		addDjangoFiles(tree, req)
 	} else {
-		if err := e.renderSpecs(tree, specs, data, ""); err != nil {
-			return err
-		}
-	}
-
-	if req.Framework != "django" {
-		addFile(tree, "requirements.txt", pythonRequirements(req.Framework, req.Database, req.UseORM))
+		addFile(tree, "requirements.txt", pythonRequirements(req))
 	}
 	addPythonDBBoilerplate(tree, req, "")
+	addOpenAPIStubs(tree, req, "")
 	return nil
 }
 
 func (e *Engine) generatePythonService(tree *FileTree, req GenerateRequest, svcRoot string, svc ServiceConfig) error {
-	specs := pythonMicroserviceTemplateSpecs(req)
 	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
 	data := map[string]any{
 		"Framework":    req.Framework,
@@ -54,70 +46,19 @@ func (e *Engine) generatePythonService(tree *FileTree, req GenerateRequest, svcR
 		"WithCRUD":     withCRUD,
 	}
 
+	if err := e.renderManifest(tree, "python/microservice", req, data, svcRoot); err != nil {
+		return err
+	}
 	if req.Framework == "django" {
-		main, err := e.registry.Render("python/microservice/main.tmpl", data)
-		if err != nil {
-			return err
-		}
-		addDjangoFilesAtRoot(tree, req, main, svcRoot)
+		addDjangoFilesAtRoot(tree, req, svcRoot)
 	} else {
-		if err := e.renderSpecs(tree, specs, data, svcRoot); err != nil {
-			return err
-		}
-		addFile(tree, path.Join(svcRoot, "requirements.txt"), pythonRequirements(req.Framework, req.Database, req.UseORM))
+		addFile(tree, path.Join(svcRoot, "requirements.txt"), pythonRequirements(req))
 	}
 	addPythonDBBoilerplate(tree, req, svcRoot)
+	addOpenAPIStubs(tree, req, svcRoot)
 	return nil
 }
 
-func pythonMonolithTemplateSpecs(req GenerateRequest) []templateSpec {
-	withCRUD := isEnabled(req.FileToggles.ExampleCRUD)
-	switch req.Architecture {
-	case "clean":
-		base := []templateSpec{
-			{Template: "python/clean/main.tmpl", Output: "app/main.py"},
-		}
-		if withCRUD {
-			return append(base, []templateSpec{
-				{Template: "python/clean/app/domain/item.tmpl", Output: "app/domain/item.py"},
-				{Template: "python/clean/app/usecases/list_items.tmpl", Output: "app/usecases/list_items.py"},
-				{Template: "python/clean/app/delivery/http/item_controller.tmpl", Output: "app/delivery/http/item_controller.py"},
-				{Template: "python/clean/app/repository/item_repository.tmpl", Output: "app/repository/item_repository.py"},
-			}...)
-		}
-		return append(base, []templateSpec{
-			{Template: "python/clean/app/domain/ping.tmpl", Output: "app/domain/ping.py"},
-			{Template: "python/clean/app/usecases/ping_usecase.tmpl", Output: "app/usecases/ping_usecase.py"},
-			{Template: "python/clean/app/delivery/http/ping_controller.tmpl", Output: "app/delivery/http/ping_controller.py"},
-			{Template: "python/clean/app/repository/ping_repository.tmpl", Output: "app/repository/ping_repository.py"},
-		}...)
-	case "hexagonal":
-		base := []templateSpec{
-			{Template: "python/hexagonal/main.tmpl", Output: "app/main.py"},
-		}
-		if withCRUD {
-			return append(base, []templateSpec{
-				{Template: "python/hexagonal/app/core/ports/item_repository_port.tmpl", Output: "app/core/ports/item_repository_port.py"},
-				{Template: "python/hexagonal/app/core/services/item_service.tmpl", Output: "app/core/services/item_service.py"},
-				{Template: "python/hexagonal/app/adapters/primary/http/item_controller.tmpl", Output: "app/adapters/primary/http/item_controller.py"},
-				{Template: "python/hexagonal/app/adapters/secondary/database/item_repository_adapter.tmpl", Output: "app/adapters/secondary/database/item_repository_adapter.py"},
-			}...)
-		}
-		return append(base, []templateSpec{
-			{Template: "python/hexagonal/app/core/ports/ping_port.tmpl", Output: "app/core/ports/ping_port.py"},
-			{Template: "python/hexagonal/app/core/services/ping_service.tmpl", Output: "app/core/services/ping_service.py"},
-			{Template: "python/hexagonal/app/adapters/primary/http/ping_controller.tmpl", Output: "app/adapters/primary/http/ping_controller.py"},
-			{Template: "python/hexagonal/app/adapters/secondary/database/ping_adapter.tmpl", Output: "app/adapters/secondary/database/ping_adapter.py"},
-		}...)
-	default:
-		return []templateSpec{{Template: fmt.Sprintf("python/%s/main.tmpl", archTemplateName(req.Architecture)), Output: "app/main.py"}}
-	}
-}
-
-func pythonMicroserviceTemplateSpecs(_ GenerateRequest) []templateSpec {
-	return []templateSpec{{Template: "python/microservice/main.tmpl", Output: "app/main.py"}}
-}
-
 func addPythonDBBoilerplate(tree *FileTree, req GenerateRequest, root string) {
 	if !isSQLDB(req.Database) {
 		return
@@ -128,7 +69,16 @@ func addPythonDBBoilerplate(tree *FileTree, req GenerateRequest, root string) {
 	}
 
 	if req.Framework == "django" {
-		addFile(tree, prefix+"api/models.py", "from django.db import models\n\nclass Item(models.Model):\n    name = models.CharField(max_length=255)\n")
+		return
+	}
+
+	if req.Database == "sqlite" {
+		if req.UseORM {
+			addFile(tree, prefix+"app/repository/sqlalchemy_session.py", "import os\nfrom sqlalchemy import create_engine, event\nfrom sqlalchemy.orm import sessionmaker\n\nDATABASE_URL = os.getenv('DATABASE_URL', 'sqlite:///data/app.db')\nengine = create_engine(DATABASE_URL, connect_args={'check_same_thread': False})\n"+sqlAlchemySQLiteForeignKeys+"SessionLocal = sessionmaker(bind=engine, autoflush=False, autocommit=False)\n")
+			addFile(tree, prefix+"app/repository/models.py", renderSQLAlchemyModels(req.Custom.Models))
+			return
+		}
+		addFile(tree, prefix+"app/repository/sql_driver.py", "import os\nimport sqlite3\n\nDATABASE_URL = os.getenv('DATABASE_URL', 'sqlite:///data/app.db')\n\ndef connect():\n    conn = sqlite3.connect(DATABASE_URL.removeprefix('sqlite:///'), check_same_thread=False)\n    conn.execute('PRAGMA foreign_keys = ON')\n    return conn\n")
 		return
 	}
 
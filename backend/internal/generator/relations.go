@@ -0,0 +1,418 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	RelationBelongsTo  = "belongs_to"
+	RelationHasMany    = "has_many"
+	RelationManyToMany = "many_to_many"
+)
+
+const relationHint = "Use belongs_to for a foreign key on this model, has_many for one on the target, or many_to_many for a join table."
+
+var (
+	relationKinds = []string{RelationBelongsTo, RelationHasMany, RelationManyToMany}
+	sqlIdentRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// modelLink is one relation between two models, whichever side declared it.
+// A foreign key link puts Column on Child referencing Parent; a many-to-many
+// link stores ParentColumn and Column in JoinTable.
+type modelLink struct {
+	Kind         string
+	Parent       string
+	Child        string
+	Column       string
+	ParentColumn string
+	JoinTable    string
+	// ParentAttr overrides the association name on Parent when the plural
+	// child name is taken by another link, e.g. author_posts.
+	ParentAttr string
+}
+
+func (l modelLink) manyToMany() bool { return l.Kind == RelationManyToMany }
+
+// attrOn is the association name on model: the singular parent on a child
+// ("user"), or the plural other side ("orders", "tags").
+func (l modelLink) attrOn(model string) string {
+	if l.manyToMany() {
+		if model == l.Parent {
+			return tableName(l.Child)
+		}
+		return tableName(l.Parent)
+	}
+	if model == l.Child {
+		return strings.TrimSuffix(l.Column, "_id")
+	}
+	if l.ParentAttr != "" {
+		return l.ParentAttr
+	}
+	return tableName(l.Child)
+}
+
+// snakeCase turns a model name into a column prefix, e.g. OrderItem -> order_item.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func foreignKeyColumn(model string) string {
+	return snakeCase(model) + "_id"
+}
+
+// modelLinks collects the relations of resolved models, dropping the second
+// declaration when both sides describe the same link.
+func modelLinks(models []DataModel) []modelLink {
+	known := map[string]bool{}
+	for _, m := range models {
+		known[m.Name] = true
+	}
+	seen := map[string]bool{}
+	var out []modelLink
+	for _, m := range models {
+		for _, rel := range m.Relations {
+			if !known[rel.Model] || rel.Model == m.Name {
+				continue
+			}
+			var l modelLink
+			switch rel.Kind {
+			case RelationBelongsTo:
+				l = modelLink{Kind: RelationBelongsTo, Parent: rel.Model, Child: m.Name, Column: rel.ForeignKey}
+			case RelationHasMany:
+				l = modelLink{Kind: RelationBelongsTo, Parent: m.Name, Child: rel.Model, Column: rel.ForeignKey}
+			case RelationManyToMany:
+				l = modelLink{Kind: RelationManyToMany, Parent: m.Name, Child: rel.Model, JoinTable: rel.JoinTable,
+					ParentColumn: foreignKeyColumn(m.Name), Column: foreignKeyColumn(rel.Model)}
+				if l.JoinTable == "" {
+					l.JoinTable = tableName(m.Name) + "_" + tableName(rel.Model)
+				}
+			default:
+				continue
+			}
+			key := l.Child + "." + l.Column
+			if l.manyToMany() {
+				pair := []string{l.Parent, l.Child}
+				slices.Sort(pair)
+				key = "m2m." + strings.Join(pair, ".")
+			} else if l.Column == "" {
+				l.Column = foreignKeyColumn(l.Parent)
+				key = l.Child + "." + l.Column
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, l)
+		}
+	}
+	nameParentSides(out)
+	return out
+}
+
+// nameParentSides prefixes the plural association name on the parent of a
+// foreign key with its column when two links would otherwise give the parent
+// the same name: author_id and editor_id on Post give User author_posts and
+// editor_posts. A <parent>_id column keeps the plain name unless a
+// many-to-many link claims it.
+func nameParentSides(links []modelLink) {
+	claims := map[string]int{}
+	m2m := map[string]bool{}
+	for _, l := range links {
+		claims[l.Parent+"."+tableName(l.Child)]++
+		if l.manyToMany() {
+			claims[l.Child+"."+tableName(l.Parent)]++
+			m2m[l.Parent+"."+tableName(l.Child)] = true
+			m2m[l.Child+"."+tableName(l.Parent)] = true
+		}
+	}
+	for i, l := range links {
+		key := l.Parent + "." + tableName(l.Child)
+		if l.manyToMany() || claims[key] < 2 || (l.Column == foreignKeyColumn(l.Parent) && !m2m[key]) {
+			continue
+		}
+		links[i].ParentAttr = strings.TrimSuffix(l.Column, "_id") + "_" + tableName(l.Child)
+	}
+}
+
+// foreignKeysOn lists the foreign key links whose column lives on model.
+func foreignKeysOn(links []modelLink, model string) []modelLink {
+	var out []modelLink
+	for _, l := range links {
+		if !l.manyToMany() && l.Child == model {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func joinLinks(links []modelLink) []modelLink {
+	var out []modelLink
+	for _, l := range links {
+		if l.manyToMany() {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// isForeignKeyField reports whether field is rendered as a foreign key column
+// of model rather than as a plain column.
+func isForeignKeyField(links []modelLink, model, field string) bool {
+	for _, l := range foreignKeysOn(links, model) {
+		if strings.EqualFold(l.Column, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderByDependency puts every model after the models it references, keeping
+// the request order otherwise. Cycles are left in request order.
+func orderByDependency(models []DataModel) []DataModel {
+	deps := map[string][]string{}
+	for _, l := range modelLinks(models) {
+		if !l.manyToMany() {
+			deps[l.Child] = append(deps[l.Child], l.Parent)
+		}
+	}
+	done := map[string]bool{}
+	placed := make([]bool, len(models))
+	out := make([]DataModel, 0, len(models))
+	for len(out) < len(models) {
+		next, fallback := -1, -1
+		for i, m := range models {
+			if placed[i] {
+				continue
+			}
+			if fallback < 0 {
+				fallback = i
+			}
+			if !slices.ContainsFunc(deps[m.Name], func(d string) bool { return !done[d] }) {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			next = fallback
+		}
+		if next < 0 {
+			break
+		}
+		placed[next] = true
+		done[models[next].Name] = true
+		out = append(out, models[next])
+	}
+	return out
+}
+
+// dependencyCycle returns the models of a foreign key cycle, if any.
+func dependencyCycle(models []DataModel) []string {
+	deps := map[string][]string{}
+	for _, l := range modelLinks(models) {
+		if !l.manyToMany() {
+			deps[l.Child] = append(deps[l.Child], l.Parent)
+		}
+	}
+	state := map[string]int{}
+	var stack []string
+	var visit func(string) []string
+	visit = func(n string) []string {
+		state[n] = 1
+		stack = append(stack, n)
+		for _, d := range deps[n] {
+			if state[d] == 1 {
+				i := slices.Index(stack, d)
+				return append(slices.Clone(stack[i:]), d)
+			}
+			if state[d] == 0 {
+				if cycle := visit(d); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = 2
+		return nil
+	}
+	for _, m := range models {
+		if state[m.Name] == 0 {
+			if cycle := visit(m.Name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+func validateModelRelations(verr *ValidationError, models []DataModel) {
+	names := map[string]bool{}
+	for _, m := range models {
+		if name := strings.TrimSpace(m.Name); name != "" {
+			names[toPascal(name)] = true
+		}
+	}
+	before := len(verr.Errors)
+	for i, m := range models {
+		for j, rel := range m.Relations {
+			ptr := fmt.Sprintf("/custom/models/%d/relations/%d", i, j)
+			kind := strings.ToLower(strings.TrimSpace(rel.Kind))
+			if !slices.Contains(relationKinds, kind) {
+				verr.add(CodeInvalidChoice, ptr+"/kind",
+					fmt.Sprintf("relation kind must be one of: %s", strings.Join(relationKinds, ", ")),
+					relationHint)
+			}
+			target := toPascal(rel.Model)
+			switch {
+			case strings.TrimSpace(rel.Model) == "" || !names[target]:
+				verr.add(CodeInvalidChoice, ptr+"/model",
+					fmt.Sprintf("relation target %q is not a model in custom.models", rel.Model),
+					"Point the relation at another entry of custom.models.")
+			case target == toPascal(m.Name):
+				verr.add(CodeInvalidChoice, ptr+"/model",
+					fmt.Sprintf("model %q cannot relate to itself", m.Name),
+					"Self-referencing relations are not supported yet.")
+			}
+			if fk := strings.TrimSpace(rel.ForeignKey); fk != "" && (!sqlIdentRegex.MatchString(fk) || !strings.HasSuffix(fk, "_id")) {
+				verr.add(CodeInvalidFormat, ptr+"/foreign_key",
+					fmt.Sprintf("foreign_key %q must be a column name ending in _id", fk),
+					"Use letters, digits and '_', e.g. author_id.")
+			}
+			if jt := strings.TrimSpace(rel.JoinTable); jt != "" && !sqlIdentRegex.MatchString(jt) {
+				verr.add(CodeInvalidFormat, ptr+"/join_table",
+					fmt.Sprintf("join_table %q is not a valid table name", jt),
+					"Use letters, digits and '_', e.g. posts_tags.")
+			}
+		}
+	}
+	if len(verr.Errors) > before {
+		return
+	}
+	if cycle := dependencyCycle(resolvedModels(models)); cycle != nil {
+		verr.add(CodeInvalidFormat, "/custom/models",
+			fmt.Sprintf("foreign keys form a cycle: %s", strings.Join(cycle, " -> ")),
+			"Drop one belongs_to/has_many relation in the cycle or turn it into many_to_many.")
+	}
+}
+
+// sqlForeignKeyType matches the id column type of each dialect.
+func sqlForeignKeyType(db string) string {
+	switch db {
+	case "mysql":
+		return "BIGINT"
+	case "sqlite":
+		return "INTEGER"
+	default:
+		return "INT"
+	}
+}
+
+// goRelationFields renders the GORM associations of model. Its foreign key
+// columns are plain fields, see goModel.
+func goRelationFields(links []modelLink, model string) []string {
+	var out []string
+	for _, l := range links {
+		attr := toPascal(l.attrOn(model))
+		tag := fmt.Sprintf("`json:\"%s,omitempty\" gorm:\"foreignKey:%s\"`", l.attrOn(model), toPascal(l.Column))
+		switch {
+		case l.manyToMany() && (l.Parent == model || l.Child == model):
+			other := l.Child
+			if l.Child == model {
+				other = l.Parent
+			}
+			out = append(out, fmt.Sprintf("%s []%s `json:\"%s,omitempty\" gorm:\"many2many:%s\"`", attr, other, l.attrOn(model), l.JoinTable))
+		case l.manyToMany():
+		case l.Child == model:
+			out = append(out, fmt.Sprintf("%s *%s %s", attr, l.Parent, tag))
+		case l.Parent == model:
+			out = append(out, fmt.Sprintf("%s []%s %s", attr, l.Child, tag))
+		}
+	}
+	return out
+}
+
+func prismaRelationName(l modelLink, column string) string {
+	if l.manyToMany() {
+		return toPascal(l.JoinTable) + "_" + column
+	}
+	return l.Child + "_" + column
+}
+
+func prismaRelationLines(links []modelLink, model string) []string {
+	var out []string
+	for _, l := range links {
+		switch {
+		case l.manyToMany() && l.Parent == model:
+			out = append(out, fmt.Sprintf("%s %s[] @relation(%q)", l.JoinTable, toPascal(l.JoinTable), prismaRelationName(l, l.ParentColumn)))
+		case l.manyToMany() && l.Child == model:
+			out = append(out, fmt.Sprintf("%s %s[] @relation(%q)", l.JoinTable, toPascal(l.JoinTable), prismaRelationName(l, l.Column)))
+		case l.manyToMany():
+		case l.Child == model:
+			out = append(out,
+				l.Column+" Int?",
+				fmt.Sprintf("%s %s? @relation(%q, fields: [%s], references: [id])", l.attrOn(model), l.Parent, prismaRelationName(l, l.Column), l.Column))
+		case l.Parent == model:
+			out = append(out, fmt.Sprintf("%s %s[] @relation(%q)", l.attrOn(model), l.Child, prismaRelationName(l, l.Column)))
+		}
+	}
+	return out
+}
+
+// prismaJoinModels renders the explicit join model of each many-to-many link
+// so Prisma uses the same join table as the SQL migrations.
+func prismaJoinModels(links []modelLink) string {
+	var b strings.Builder
+	for _, l := range joinLinks(links) {
+		fmt.Fprintf(&b, "model %s {\n  %s Int\n  %s Int\n", toPascal(l.JoinTable), l.ParentColumn, l.Column)
+		fmt.Fprintf(&b, "  %s %s @relation(%q, fields: [%s], references: [id], onDelete: Cascade)\n", strings.TrimSuffix(l.ParentColumn, "_id"), l.Parent, prismaRelationName(l, l.ParentColumn), l.ParentColumn)
+		fmt.Fprintf(&b, "  %s %s @relation(%q, fields: [%s], references: [id], onDelete: Cascade)\n", strings.TrimSuffix(l.Column, "_id"), l.Child, prismaRelationName(l, l.Column), l.Column)
+		fmt.Fprintf(&b, "\n  @@id([%s, %s])\n  @@map(%q)\n}\n\n", l.ParentColumn, l.Column, l.JoinTable)
+	}
+	return b.String()
+}
+
+func sqlalchemyRelationLines(links []modelLink, model string) []string {
+	var out []string
+	for _, l := range links {
+		switch {
+		case l.manyToMany() && (l.Parent == model || l.Child == model):
+			other := l.Child
+			if l.Child == model {
+				other = l.Parent
+			}
+			out = append(out, fmt.Sprintf("%s: Mapped[list[\"%s\"]] = relationship(secondary=%q, back_populates=%q)", l.attrOn(model), other, l.JoinTable, l.attrOn(other)))
+		case l.manyToMany():
+		case l.Child == model:
+			out = append(out,
+				fmt.Sprintf("%s: Mapped[int | None] = mapped_column(ForeignKey(\"%s.id\"))", l.Column, tableName(l.Parent)),
+				fmt.Sprintf("%s: Mapped[\"%s | None\"] = relationship(back_populates=%q, foreign_keys=[%s])", l.attrOn(model), l.Parent, l.attrOn(l.Parent), l.Column))
+		case l.Parent == model:
+			out = append(out, fmt.Sprintf("%s: Mapped[list[\"%s\"]] = relationship(back_populates=%q, foreign_keys=\"%s.%s\")", l.attrOn(model), l.Child, l.attrOn(l.Child), l.Child, l.Column))
+		}
+	}
+	return out
+}
+
+func sqlalchemyJoinTables(links []modelLink) string {
+	var b strings.Builder
+	for _, l := range joinLinks(links) {
+		fmt.Fprintf(&b, "%s = Table(\n    %q,\n    Base.metadata,\n", l.JoinTable, l.JoinTable)
+		fmt.Fprintf(&b, "    Column(%q, ForeignKey(\"%s.id\", ondelete=\"CASCADE\"), primary_key=True),\n", l.ParentColumn, tableName(l.Parent))
+		fmt.Fprintf(&b, "    Column(%q, ForeignKey(\"%s.id\", ondelete=\"CASCADE\"), primary_key=True),\n)\n\n", l.Column, tableName(l.Child))
+	}
+	return b.String()
+}
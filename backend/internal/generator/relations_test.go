@@ -0,0 +1,242 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func relationModels() []DataModel {
+	return []DataModel{
+		{Name: "order", Fields: []DataField{{Name: "total", Type: "float"}, {Name: "user_id", Type: "string"}}, Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "user"}}},
+		{Name: "user", Fields: []DataField{{Name: "email", Type: "string"}}, Relations: []DataRelation{{Kind: RelationHasMany, Model: "order"}}},
+		{Name: "post", Fields: []DataField{{Name: "title", Type: "string"}}, Relations: []DataRelation{
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "author_id"},
+			{Kind: RelationManyToMany, Model: "tag"},
+		}},
+		{Name: "tag", Fields: []DataField{{Name: "label", Type: "string"}}, Relations: []DataRelation{{Kind: RelationManyToMany, Model: "post"}}},
+	}
+}
+
+// goDomainSource renders models as Go domain structs of a GORM project.
+func goDomainSource(t *testing.T, models []DataModel) string {
+	t.Helper()
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql", UseORM: true, Custom: CustomOptions{Models: models}})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	return domainSource(project.Tree)
+}
+
+// domainSource concatenates the internal/domain files of tree in path order.
+func domainSource(tree FileTree) string {
+	var b strings.Builder
+	for _, p := range fileNamesSorted(tree.Files) {
+		if strings.HasPrefix(p, "internal/domain/") {
+			b.WriteString(tree.Files[p])
+		}
+	}
+	return b.String()
+}
+
+func TestRelationsRenderAcrossSchemas(t *testing.T) {
+	t.Parallel()
+
+	models := relationModels()
+	order := []string{}
+	for _, m := range resolvedModels(models) {
+		order = append(order, m.Name)
+	}
+	if got := strings.Join(order, ","); got != "User,Order,Post,Tag" {
+		t.Fatalf("expected referenced models first, got %s", got)
+	}
+
+	sql := renderSQLTablesTemplate("mysql", models, false)
+	for _, want := range []string{
+		"  total DECIMAL(10,2),\n  user_id BIGINT,\n  FOREIGN KEY (user_id) REFERENCES users(id)\n);",
+		"  author_id BIGINT,\n  FOREIGN KEY (author_id) REFERENCES users(id)\n);",
+		"CREATE TABLE IF NOT EXISTS posts_tags (\n  post_id BIGINT NOT NULL,\n  tag_id BIGINT NOT NULL,\n  PRIMARY KEY (post_id, tag_id),",
+		"FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("expected SQL to contain %q, got:\n%s", want, sql)
+		}
+	}
+	if strings.Contains(sql, "user_id VARCHAR") || strings.Count(sql, "CREATE TABLE") != 5 {
+		t.Fatalf("expected the declared user_id field to become the foreign key, got:\n%s", sql)
+	}
+	if got := dropTablesSQL(models); !strings.HasPrefix(got, "DROP TABLE IF EXISTS posts_tags;") || !strings.HasSuffix(got, "DROP TABLE IF EXISTS users;\n") {
+		t.Fatalf("expected drops in reverse dependency order, got:\n%s", got)
+	}
+
+	goModels := goDomainSource(t, models)
+	for _, want := range []string{
+		"UserId *int `json:\"user_id\" gorm:\"column:user_id\"`",
+		"User *User `json:\"user,omitempty\" gorm:\"foreignKey:UserId\"`",
+		"Orders []Order `json:\"orders,omitempty\" gorm:\"foreignKey:UserId\"`",
+		"Posts []Post `json:\"posts,omitempty\" gorm:\"foreignKey:AuthorId\"`",
+		"Tags []Tag `json:\"tags,omitempty\" gorm:\"many2many:posts_tags\"`",
+	} {
+		if !strings.Contains(goModels, want) {
+			t.Fatalf("expected GORM models to contain %q, got:\n%s", want, goModels)
+		}
+	}
+
+	prisma := renderPrismaSchema("postgresql", models)
+	for _, want := range []string{
+		`user User? @relation("Order_user_id", fields: [user_id], references: [id])`,
+		`orders Order[] @relation("Order_user_id")`,
+		`posts_tags PostsTags[] @relation("PostsTags_tag_id")`,
+		"model PostsTags {",
+		`@@map("posts_tags")`,
+	} {
+		if !strings.Contains(prisma, want) {
+			t.Fatalf("expected Prisma schema to contain %q, got:\n%s", want, prisma)
+		}
+	}
+
+	sqlalchemy := renderSQLAlchemyModels(models)
+	for _, want := range []string{
+		`posts_tags = Table(`,
+		`user_id: Mapped[int | None] = mapped_column(ForeignKey("users.id"))`,
+		`user: Mapped["User | None"] = relationship(back_populates="orders", foreign_keys=[user_id])`,
+		`posts: Mapped[list["Post"]] = relationship(back_populates="author", foreign_keys="Post.author_id")`,
+		`tags: Mapped[list["Tag"]] = relationship(secondary="posts_tags", back_populates="posts")`,
+	} {
+		if !strings.Contains(sqlalchemy, want) {
+			t.Fatalf("expected SQLAlchemy models to contain %q, got:\n%s", want, sqlalchemy)
+		}
+	}
+}
+
+func TestRelationsReachMigrationTools(t *testing.T) {
+	t.Parallel()
+
+	models := relationModels()
+	if got := knexInitMigration(models); !strings.Contains(got, "table.integer('user_id').unsigned().references('id').inTable('users');") ||
+		!strings.Contains(got, "table.primary(['post_id', 'tag_id']);") {
+		t.Fatalf("expected knex foreign keys and join table, got:\n%s", got)
+	}
+	if got := alembicInitRevision(models); !strings.Contains(got, `sa.Column("author_id", sa.Integer(), sa.ForeignKey("users.id")),`) {
+		t.Fatalf("expected alembic foreign key, got:\n%s", got)
+	}
+	got := djangoInitMigration(models)
+	if !strings.Contains(got, "('user', models.ForeignKey('api.user', on_delete=models.CASCADE, null=True, related_name='orders', db_column='user_id'))") ||
+		!strings.Contains(got, "field=models.ManyToManyField('api.tag', related_name='posts', db_table='posts_tags')") {
+		t.Fatalf("expected django relations, got:\n%s", got)
+	}
+}
+
+func TestRelationsToSameParentGetDistinctNames(t *testing.T) {
+	t.Parallel()
+
+	models := []DataModel{
+		{Name: "user", Fields: []DataField{{Name: "email", Type: "string"}}},
+		{Name: "post", Fields: []DataField{{Name: "title", Type: "string"}}, Relations: []DataRelation{
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "author_id"},
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "editor_id"},
+		}},
+		{Name: "follow", Relations: []DataRelation{
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "follower_id"},
+			{Kind: RelationBelongsTo, Model: "user"},
+		}},
+	}
+	for name, wants := range map[string][]string{
+		"GORM": {
+			"AuthorPosts []Post `json:\"author_posts,omitempty\" gorm:\"foreignKey:AuthorId\"`",
+			"EditorPosts []Post `json:\"editor_posts,omitempty\" gorm:\"foreignKey:EditorId\"`",
+			"FollowerFollows []Follow `json:\"follower_follows,omitempty\" gorm:\"foreignKey:FollowerId\"`",
+			"Follows []Follow `json:\"follows,omitempty\" gorm:\"foreignKey:UserId\"`",
+		},
+		"Prisma": {`author_posts Post[] @relation("Post_author_id")`, `editor_posts Post[] @relation("Post_editor_id")`},
+		"SQLAlchemy": {
+			`editor: Mapped["User | None"] = relationship(back_populates="editor_posts", foreign_keys=[editor_id])`,
+			`follower_follows: Mapped[list["Follow"]] = relationship(back_populates="follower", foreign_keys="Follow.follower_id")`,
+		},
+		"Django": {"related_name='author_posts', db_column='author_id'", "related_name='editor_posts', db_column='editor_id'"},
+	} {
+		got := map[string]string{
+			"GORM":       goDomainSource(t, models),
+			"Prisma":     renderPrismaSchema("postgresql", models),
+			"SQLAlchemy": renderSQLAlchemyModels(models),
+			"Django":     djangoInitMigration(models),
+		}[name]
+		for _, want := range wants {
+			if !strings.Contains(got, want) {
+				t.Fatalf("expected %s to contain %q, got:\n%s", name, want, got)
+			}
+		}
+	}
+}
+
+func TestValidateRelations(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	base := GenerateRequest{Language: "go", Framework: "gin", Architecture: "mvp", Database: "postgresql"}
+
+	req := base
+	req.Custom.Models = []DataModel{
+		{Name: "post", Relations: []DataRelation{
+			{Kind: "owns", Model: "user"},
+			{Kind: RelationBelongsTo, Model: "author"},
+			{Kind: RelationBelongsTo, Model: "post"},
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "owner"},
+		}},
+		{Name: "user"},
+	}
+	_, err := engine.Build(context.Background(), req)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	got := map[string]string{}
+	for _, fe := range verr.Errors {
+		got[fe.Path] = fe.Code
+	}
+	for p, code := range map[string]string{
+		"/custom/models/0/relations/0/kind":        CodeInvalidChoice,
+		"/custom/models/0/relations/1/model":       CodeInvalidChoice,
+		"/custom/models/0/relations/2/model":       CodeInvalidChoice,
+		"/custom/models/0/relations/3/foreign_key": CodeInvalidFormat,
+	} {
+		if got[p] != code {
+			t.Fatalf("expected %s at %s, got %v", code, p, got)
+		}
+	}
+
+	req = base
+	req.Custom.Models = []DataModel{
+		{Name: "a", Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "b"}}},
+		{Name: "b", Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "a"}}},
+	}
+	_, err = engine.Build(context.Background(), req)
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/custom/models" || !strings.Contains(verr.Error(), "A -> B -> A") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestGoRepositoriesStoreForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{Language: "go", Framework: "gin", Architecture: "mvp", Database: "postgresql", Custom: CustomOptions{Models: []DataModel{
+		{Name: "user", Fields: []DataField{{Name: "email", Type: "string"}}},
+		{Name: "post", Fields: []DataField{{Name: "title", Type: "string"}}, Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "user", ForeignKey: "author_id"}}},
+	}}})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	files := project.Tree.Files
+	if want := "AuthorId *int `json:\"author_id\" gorm:\"column:author_id\"`"; !strings.Contains(files["internal/domain/post.go"], want) {
+		t.Fatalf("expected the domain struct to carry %q, got:\n%s", want, files["internal/domain/post.go"])
+	}
+	if want := `"SELECT id, title, author_id FROM posts WHERE id = $1"`; !strings.Contains(files["internal/repository/post_repository.go"], want) {
+		t.Fatalf("expected the repository to read the foreign key, got:\n%s", files["internal/repository/post_repository.go"])
+	}
+	for p := range files {
+		if strings.HasPrefix(p, "internal/models/") {
+			t.Fatalf("expected the domain structs to be the only struct set, got %s", p)
+		}
+	}
+}
@@ -1,11 +1,63 @@
 package generator
 
 import (
-	"errors"
 	"slices"
 	"strings"
 )
 
+// Incompatibility is a stack combination the rule engine rejects outright.
+// Empty selectors match any value.
+type Incompatibility struct {
+	Code      string `json:"code"`
+	Language  string `json:"language,omitempty"`
+	Framework string `json:"framework,omitempty"`
+	Database  string `json:"db,omitempty"`
+	Message   string `json:"message"`
+}
+
+var stackIncompatibilities = []Incompatibility{
+	{Code: "incompatible.django_mongodb", Language: "python", Framework: "django", Database: "mongodb", Message: "django framework is not compatible with mongodb in this generator"},
+}
+
+// nonORMDatabases never receive SQL ORM boilerplate.
+var nonORMDatabases = []string{"none", "mongodb"}
+
+func supportsSQLORM(db string) bool {
+	return !slices.Contains(nonORMDatabases, db)
+}
+
+// RuleError is a hard rule-engine failure: the request is well-formed but the
+// selected combination cannot be generated.
+type RuleError struct {
+	Code    string   `json:"code"`
+	Message string   `json:"message"`
+	Paths   []string `json:"paths,omitempty"`
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+func (i Incompatibility) paths() []string {
+	out := make([]string, 0, 3)
+	if i.Language != "" {
+		out = append(out, "/language")
+	}
+	if i.Framework != "" {
+		out = append(out, "/framework")
+	}
+	if i.Database != "" {
+		out = append(out, "/db")
+	}
+	return out
+}
+
+func (i Incompatibility) matches(req GenerateRequest) bool {
+	return (i.Language == "" || i.Language == req.Language) &&
+		(i.Framework == "" || i.Framework == req.Framework) &&
+		(i.Database == "" || i.Database == req.Database)
+}
+
 // ApplyRuleEngine normalizes cross-field behavior and applies safe auto-corrections
 // before strict validation runs.
 func ApplyRuleEngine(req GenerateRequest) (GenerateRequest, []string, error) {
@@ -33,7 +85,7 @@ func ApplyRuleEngine(req GenerateRequest) (GenerateRequest, []string, error) {
 	}
 
 	// ORM corrections.
-	if req.UseORM && (req.Database == "none" || req.Database == "mongodb") {
+	if req.UseORM && !supportsSQLORM(req.Database) {
 		req.UseORM = false
 		warnings = append(warnings, "use_orm was disabled because the selected database does not use SQL ORM in this generator.")
 	}
@@ -42,9 +94,16 @@ func ApplyRuleEngine(req GenerateRequest) (GenerateRequest, []string, error) {
 		warnings = append(warnings, "use_orm was disabled because Django already uses its built-in ORM.")
 	}
 
+	if req.MigrationTool != "" && !isSQLDB(req.Database) {
+		req.MigrationTool = ""
+		warnings = append(warnings, "migration_tool was ignored because the selected database is not a SQL database.")
+	}
+
 	// Framework/database compatibility checks.
-	if req.Language == "python" && req.Framework == "django" && req.Database == "mongodb" {
-		return req, warnings, errors.New("django framework is not compatible with mongodb in this generator")
+	for _, rule := range stackIncompatibilities {
+		if rule.matches(req) {
+			return req, warnings, &RuleError{Code: rule.Code, Message: rule.Message, Paths: rule.paths()}
+		}
 	}
 
 	// Service communication defaults for microservices.
@@ -1,6 +1,9 @@
 package generator
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestApplyRuleEngine(t *testing.T) {
 	t.Parallel()
@@ -98,5 +101,9 @@ func TestApplyRuleEngine(t *testing.T) {
 		if err == nil {
 			t.Fatalf("expected incompatibility error")
 		}
+		var rerr *RuleError
+		if !errors.As(err, &rerr) || rerr.Code != "incompatible.django_mongodb" {
+			t.Fatalf("expected *RuleError with django/mongodb code, got %T (%v)", err, err)
+		}
 	})
 }
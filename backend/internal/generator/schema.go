@@ -0,0 +1,253 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	CodeUnknownField = "unknown_field"
+	CodeInvalidJSON  = "invalid_json"
+
+	GenerateRequestSchemaID = "https://stacksprint.dev/schema/generate-request.json"
+)
+
+// schemaEnums pins enum values onto schema properties, keyed by dotted JSON
+// path with [] marking array items.
+func schemaEnums() map[string][]string {
+	frameworks := make([]string, 0)
+	for _, lang := range languageOptions {
+		frameworks = append(frameworks, frameworksFor(lang)...)
+	}
+	return map[string][]string{
+		"language":                         languageOptions,
+		"framework":                        frameworks,
+		"architecture":                     architectureOptions,
+		"db":                               databaseOptions,
+		"service_communication":            communicationOptions,
+		"root.mode":                        rootModeOptions,
+		"root.on_conflict":                 conflictOptions,
+		"custom.add_files[].mode":          customFileModes,
+		"migration_tool":                   migrationToolOptions,
+		"custom.models[].relations[].kind": relationKinds,
+	}
+}
+
+// GenerateRequestSchema returns a JSON Schema (draft 2020-12) derived from the
+// GenerateRequest struct tags, so it cannot drift from what the API decodes.
+func GenerateRequestSchema() map[string]any {
+	schema := schemaForType(reflect.TypeOf(GenerateRequest{}), "", schemaEnums())
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = GenerateRequestSchemaID
+	schema["title"] = "StackSprint GenerateRequest"
+	return schema
+}
+
+func schemaForType(t reflect.Type, path string, enums map[string][]string) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		inner := schemaForType(t.Elem(), path, enums)
+		if typ, ok := inner["type"].(string); ok {
+			inner["type"] = []string{typ, "null"}
+		}
+		return inner
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]any{}
+		for _, f := range jsonFields(t) {
+			props[f.name] = schemaForType(f.typ, joinSchemaPath(path, f.name), enums)
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaForType(t.Elem(), path+"[]", enums),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaForType(t.Elem(), path+"[]", enums),
+		}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		out := map[string]any{"type": "string"}
+		if values, ok := enums[path]; ok {
+			out["enum"] = values
+		}
+		return out
+	default:
+		return map[string]any{}
+	}
+}
+
+func joinSchemaPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+func jsonFields(t reflect.Type) []jsonField {
+	out := make([]jsonField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		out = append(out, jsonField{name: name, typ: field.Type})
+	}
+	return out
+}
+
+// DecodeGenerateRequest parses a JSON request body. In strict mode every key
+// that does not map onto a GenerateRequest field is reported as a
+// ValidationError with its JSON pointer.
+func DecodeGenerateRequest(body []byte, strict bool) (GenerateRequest, error) {
+	var req GenerateRequest
+	if strict {
+		var raw any
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return req, invalidJSONError(err)
+		}
+		verr := &ValidationError{}
+		collectUnknownFields(verr, raw, reflect.TypeOf(req), "")
+		if len(verr.Errors) > 0 {
+			return req, verr
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if err := dec.Decode(&req); err != nil {
+		return req, invalidJSONError(err)
+	}
+	return req, nil
+}
+
+func invalidJSONError(err error) error {
+	return &ValidationError{Errors: []FieldError{{
+		Code:    CodeInvalidJSON,
+		Path:    "",
+		Message: fmt.Sprintf("invalid JSON body: %v", err),
+		Hint:    "Check the body against /schema/generate-request.json.",
+	}}}
+}
+
+func collectUnknownFields(verr *ValidationError, value any, t reflect.Type, pointer string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if t.Kind() == reflect.Map {
+			for _, key := range sortedKeys(v) {
+				collectUnknownFields(verr, v[key], t.Elem(), pointer+"/"+escapeJSONPointer(key))
+			}
+			return
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		fields := jsonFields(t)
+		byName := make(map[string]reflect.Type, len(fields))
+		names := make([]string, 0, len(fields))
+		for _, f := range fields {
+			byName[f.name] = f.typ
+			names = append(names, f.name)
+		}
+		for _, key := range sortedKeys(v) {
+			child := pointer + "/" + escapeJSONPointer(key)
+			ft, ok := byName[key]
+			if !ok {
+				hint := "Remove the field; see /schema/generate-request.json for allowed keys."
+				if suggestion := closestName(key, names); suggestion != "" {
+					hint = fmt.Sprintf("Did you mean %q?", suggestion)
+				}
+				verr.add(CodeUnknownField, child, fmt.Sprintf("unknown field %q", key), hint)
+				continue
+			}
+			collectUnknownFields(verr, v[key], ft, child)
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, item := range v {
+			collectUnknownFields(verr, item, t.Elem(), fmt.Sprintf("%s/%d", pointer, i))
+		}
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// closestName suggests a known key for likely typos such as "jwtauth" or
+// "file_toggle".
+func closestName(key string, names []string) string {
+	normalize := func(s string) string {
+		return strings.ReplaceAll(strings.ToLower(s), "_", "")
+	}
+	best, bestDist := "", 3
+	for _, name := range names {
+		d := editDistance(normalize(key), normalize(name))
+		if d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
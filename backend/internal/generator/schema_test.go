@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateRequestSchemaCoversRequestFields(t *testing.T) {
+	t.Parallel()
+
+	schema := GenerateRequestSchema()
+	props, ok := schema["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected top-level properties")
+	}
+	if schema["additionalProperties"] != false {
+		t.Fatalf("expected additionalProperties=false at top level")
+	}
+	for _, key := range []string{"language", "db", "file_toggles", "custom", "root", "service_communication"} {
+		if _, ok := props[key]; !ok {
+			t.Fatalf("expected schema property %q", key)
+		}
+	}
+
+	lang := props["language"].(map[string]any)
+	if enum, ok := lang["enum"].([]string); !ok || len(enum) != len(languageOptions) {
+		t.Fatalf("expected language enum from validator table, got %v", lang["enum"])
+	}
+
+	toggles := props["file_toggles"].(map[string]any)["properties"].(map[string]any)
+	env := toggles["env"].(map[string]any)
+	if types, ok := env["type"].([]string); !ok || len(types) != 2 {
+		t.Fatalf("expected nullable boolean for *bool toggle, got %v", env["type"])
+	}
+}
+
+func TestDecodeGenerateRequestStrictRejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{
+		"language": "go",
+		"framework": "gin",
+		"file_toggle": {"env": false},
+		"features": {"jwtauth": true},
+		"services": [{"name": "users", "port": 8081, "host": "x"}]
+	}`)
+
+	if _, err := DecodeGenerateRequest(body, false); err != nil {
+		t.Fatalf("lenient decode should ignore unknown fields: %v", err)
+	}
+
+	_, err := DecodeGenerateRequest(body, true)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %T (%v)", err, err)
+	}
+	got := map[string]FieldError{}
+	for _, fe := range verr.Errors {
+		got[fe.Path] = fe
+	}
+	for _, path := range []string{"/file_toggle", "/features/jwtauth", "/services/0/host"} {
+		fe, ok := got[path]
+		if !ok || fe.Code != CodeUnknownField {
+			t.Fatalf("expected unknown_field at %s, got %+v", path, verr.Errors)
+		}
+	}
+	if got["/file_toggle"].Hint != `Did you mean "file_toggles"?` {
+		t.Fatalf("expected suggestion for file_toggle, got %q", got["/file_toggle"].Hint)
+	}
+	if got["/features/jwtauth"].Hint != `Did you mean "jwt_auth"?` {
+		t.Fatalf("expected suggestion for jwtauth, got %q", got["/features/jwtauth"].Hint)
+	}
+}
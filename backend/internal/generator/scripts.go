@@ -51,15 +51,12 @@ func buildBash(req GenerateRequest, tree FileTree) string {
 		b.WriteString("\n")
 	}
 
-	files := fileNamesSorted(tree.Files)
-	for _, f := range files {
-		content := tree.Files[f]
-		b.WriteString(fmt.Sprintf("cat > %q <<'%s'\n", f, bashHeredocDelimiter))
-		b.WriteString(content)
-		if !strings.HasSuffix(content, "\n") {
-			b.WriteString("\n")
-		}
-		b.WriteString(bashHeredocDelimiter + "\n\n")
+	policy := req.Root.ConflictPolicy()
+	if policy == ConflictFail {
+		writeBashConflictCheck(&b, tree)
+	}
+	for _, f := range fileNamesSorted(tree.Files) {
+		writeBashFile(&b, f, tree.Files[f], policy)
 	}
 
 	b.WriteString("echo \"StackSprint project generated successfully.\"\n")
@@ -92,10 +89,12 @@ func buildPowerShell(req GenerateRequest, tree FileTree) string {
 		b.WriteString("\n")
 	}
 
-	files := fileNamesSorted(tree.Files)
-	for _, f := range files {
-		escaped := strings.ReplaceAll(f, "'", "''")
-		b.WriteString(fmt.Sprintf("@'\n%s\n'@ | Set-Content -NoNewline '%s'\n\n", trimFinalNewline(tree.Files[f]), escaped))
+	policy := req.Root.ConflictPolicy()
+	if policy == ConflictFail {
+		writePowerShellConflictCheck(&b, tree)
+	}
+	for _, f := range fileNamesSorted(tree.Files) {
+		writePowerShellFile(&b, f, tree.Files[f], policy)
 	}
 
 	b.WriteString("Write-Host 'StackSprint project generated successfully.'\n")
@@ -221,6 +220,26 @@ func buildGenerationWarnings(req GenerateRequest) []string {
 	if req.Framework == "django" && req.Language == "python" && req.UseORM && req.Database != "none" {
 		warnings = append(warnings, "Django uses built-in ORM; SQLAlchemy toggle is not applied for Django mode.")
 	}
+	if msg := inMemoryRepositories(req); msg != "" {
+		warnings = append(warnings, msg)
+	}
 
 	return warnings
 }
+
+// inMemoryRepositories explains why the CRUD repositories of req do not
+// persist anything although a database is selected, or returns "".
+func inMemoryRepositories(req GenerateRequest) string {
+	if !isEnabled(req.FileToggles.ExampleCRUD) {
+		return ""
+	}
+	switch {
+	case req.Language == "go" && req.Database == "mongodb":
+		return "Go repositories keep data in memory with mongodb; the collection repositories in internal/db are generated but not wired into the services."
+	case req.Language == "node" && isSQLDB(req.Database) && !req.UseORM:
+		return "Node.js repositories keep data in memory without use_orm; enable use_orm for Prisma, or build on src/db/sqlClient.js to persist."
+	case req.Language == "python" && req.Framework != "django" && isSQLDB(req.Database) && !req.UseORM:
+		return "FastAPI repositories keep data in memory without use_orm; enable use_orm for SQLAlchemy, or build on app/repository/sql_driver.py to persist."
+	}
+	return ""
+}
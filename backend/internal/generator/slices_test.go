@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestPerModelSlices builds each stack for one invoice model. An entry with no
+// snippets only has to exist.
+func TestPerModelSlices(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		req   GenerateRequest
+		files map[string][]string
+	}{
+		{name: "go clean gin orm", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql", UseORM: true}, files: map[string][]string{
+			"internal/domain/invoice.go":                {},
+			"internal/repository/invoice_repository.go": {},
+			"internal/usecase/invoice_usecase.go":       {},
+			"cmd/server/main.go":                        {"registerRoutes(r, connect())", "db.MustConnectWithRetry("},
+			"cmd/server/routes.go":                      {"delivery.NewInvoiceHandler(usecase.NewInvoiceUsecase(repository.NewInvoiceRepository(conn))).Register(v1)"},
+			"internal/delivery/http/invoice_handler.go": {
+				`g := r.Group("/invoices")`, "g.PATCH(\"/:id\", h.Patch)", "c.JSON(http.StatusCreated, entity)", "c.Status(http.StatusNoContent)",
+			},
+			"internal/delivery/http/errors.go": {"http.StatusUnprocessableEntity", "domain.ErrNotFound"},
+		}},
+		{name: "go clean sql postgres", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql"}, files: map[string][]string{
+			"internal/repository/invoice_repository.go": {`"SELECT id, number FROM invoices WHERE id = $1"`, "errors.Is(err, sql.ErrNoRows)", `insertSQL("invoices", columns)`},
+			"internal/repository/sql.go":                {`func bind(n int) string { return "$" + strconv.Itoa(n) }`, `" RETURNING id"`},
+			"cmd/server/routes.go":                      {"func registerRoutes(r gin.IRouter, conn *sql.DB)"},
+		}},
+		{name: "go hexagonal", req: GenerateRequest{Language: "go", Framework: "fiber", Architecture: "hexagonal", Database: "none"}, files: map[string][]string{
+			"core/domain/invoice.go": {}, "core/ports/invoice_repository.go": {}, "core/services/invoice_service.go": {}, "adapters/primary/http/invoice_handler.go": {}, "adapters/secondary/database/invoice_repository.go": {},
+		}},
+		{name: "go hexagonal gorm", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "hexagonal", Database: "sqlite", UseORM: true}, files: map[string][]string{
+			"adapters/secondary/database/invoice_repository.go": {"r.db.WithContext(ctx).Create(entity)"},
+		}},
+		{name: "go modular fiber", req: GenerateRequest{Language: "go", Framework: "fiber", Architecture: "modular-monolith", Database: "none"}, files: map[string][]string{
+			"internal/modules/invoice/model.go":   {},
+			"internal/modules/invoice/store.go":   {},
+			"cmd/server/main.go":                  {"registerRoutes(app)"},
+			"cmd/server/routes.go":                {"invoice.New().Handler.Register(v1)", "func registerRoutes(r fiber.Router)"},
+			"internal/modules/invoice/handler.go": {"g.Delete(\"/:id\", h.Delete)", "pagination.FromQuery(c.Query(\"limit\"), c.Query(\"offset\"))"},
+			"internal/modules/invoice/service.go": {`fmt.Errorf("%w: %w", ErrInvalid, err)`},
+		}},
+		{name: "go modular sql mysql", req: GenerateRequest{Language: "go", Framework: "fiber", Architecture: "modular-monolith", Database: "mysql"}, files: map[string][]string{
+			"internal/modules/invoice/store.go":  {`"UPDATE invoices SET number = ? WHERE id = ?"`, "res.LastInsertId()"},
+			"internal/modules/invoice/module.go": {"func New(db *sql.DB) *Module"},
+		}},
+		{name: "go microservice", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "microservices", Database: "none", Services: []ServiceConfig{{Name: "billing", Port: 8081}, {Name: "orders", Port: 8082}}}, files: map[string][]string{
+			"services/billing/cmd/server/routes.go": {"handlers.NewInvoiceHandler(services.NewInvoiceService(repository.NewInvoiceRepository())).Register(v1)"},
+		}},
+		{name: "node mvp mongo", req: GenerateRequest{Language: "node", Framework: "express", Architecture: "mvp", Database: "mongodb"}, files: map[string][]string{
+			"src/models/invoice.js": {}, "src/repositories/invoiceRepository.js": {}, "src/services/invoiceService.js": {}, "src/routes/invoiceRoutes.js": {},
+		}},
+		{name: "node mvp prisma", req: GenerateRequest{Language: "node", Framework: "express", Architecture: "mvp", Database: "postgresql", UseORM: true}, files: map[string][]string{
+			"src/repositories/invoiceRepository.js": {"db.invoice.findMany({ orderBy: { id: 'asc' }, skip: offset, take: limit })"},
+			"src/routes/invoiceRoutes.js":           {"parsePagination(req.query)"},
+			"Dockerfile":                            {"COPY prisma ./prisma\nRUN npm install && npx prisma generate\nCOPY . ."},
+		}},
+		{name: "node hexagonal", req: GenerateRequest{Language: "node", Framework: "fastify", Architecture: "hexagonal", Database: "postgresql"}, files: map[string][]string{
+			"src/core/domain/invoice.js": {}, "src/core/ports/invoiceRepositoryPort.js": {}, "src/core/services/invoiceService.js": {}, "src/adapters/secondary/database/invoiceRepositoryAdapter.js": {},
+		}},
+		{name: "python clean", req: GenerateRequest{Language: "python", Framework: "fastapi", Architecture: "clean", Database: "postgresql"}, files: map[string][]string{
+			"app/domain/invoice.py": {}, "app/repository/invoice_repository.py": {}, "app/usecases/invoice_usecases.py": {}, "app/delivery/http/invoice_controller.py": {},
+		}},
+		{name: "python mvp sqlalchemy", req: GenerateRequest{Language: "python", Framework: "fastapi", Architecture: "mvp", Database: "postgresql", UseORM: true}, files: map[string][]string{
+			"app/repository/invoice_repository.py": {"from app.models_orm import Invoice as InvoiceRow", "select(InvoiceRow).order_by(InvoiceRow.id).offset(offset).limit(limit)"},
+			"app/routers/invoice.py":               {"limit, offset = parse_pagination(request.query_params)"},
+			"app/db.py":                            {"'postgresql+psycopg://'"},
+		}},
+		{name: "python modular mongo", req: GenerateRequest{Language: "python", Framework: "fastapi", Architecture: "modular-monolith", Database: "mongodb"}, files: map[string][]string{
+			"app/modules/invoice/schemas.py": {}, "app/modules/invoice/repository.py": {}, "app/modules/invoice/service.py": {}, "app/modules/invoice/router.py": {},
+		}},
+		{name: "django", req: GenerateRequest{Language: "python", Framework: "django", Architecture: "mvp", Database: "postgresql", UseORM: true}, files: map[string][]string{
+			"api/models.py": {}, "api/serializers.py": {}, "api/urls.py": {},
+			"api/views.py": {"class InvoiceViewSet(LimitOffsetMixin, viewsets.ModelViewSet)", "queryset[offset:offset + limit]"},
+		}},
+	}
+	engine := testEngine(t)
+	for _, tc := range cases {
+		tc.req.Custom.Models = []DataModel{{Name: "invoice", Fields: []DataField{{Name: "number", Type: "string", Required: true}}}}
+		project, err := engine.Build(context.Background(), tc.req)
+		if err != nil {
+			t.Fatalf("%s: build: %v", tc.name, err)
+		}
+		for path, wants := range tc.files {
+			body, ok := project.Tree.Files[path]
+			if !ok {
+				t.Fatalf("%s: expected %s to be generated", tc.name, path)
+			}
+			for _, want := range wants {
+				if !strings.Contains(body, want) {
+					t.Fatalf("%s: expected %s to contain %q, got:\n%s", tc.name, path, want, body)
+				}
+			}
+		}
+	}
+}
+
+func TestNoItemStubsNextToSlices(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	for _, req := range []GenerateRequest{
+		{Language: "node", Framework: "express", Architecture: "mvp", Database: "none"},
+		{Language: "python", Framework: "fastapi", Architecture: "clean", Database: "none"},
+	} {
+		req.Custom.Models = []DataModel{{Name: "invoice", Fields: []DataField{{Name: "number", Type: "string"}}}}
+		project, err := engine.Build(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: build: %v", req.Language, err)
+		}
+		for _, stub := range []string{"src/routes/items.js", "app/items.py"} {
+			if _, ok := project.Tree.Files[stub]; ok {
+				t.Fatalf("%s: expected no unreferenced %s next to the invoice slice", req.Language, stub)
+			}
+		}
+	}
+}
+
+func TestGoRoutesSkippedWithoutCRUD(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{Language: "go", Framework: "gin", Architecture: "mvp", Database: "none", FileToggles: FileToggleOptions{ExampleCRUD: &disabled}})
+	if err != nil {
+		t.Fatalf("build without crud: %v", err)
+	}
+	if _, ok := project.Tree.Files["cmd/server/routes.go"]; ok {
+		t.Fatalf("expected no routes.go without example CRUD")
+	}
+	if strings.Contains(project.Tree.Files["cmd/server/main.go"], "registerRoutes") {
+		t.Fatalf("expected main.go not to register model routes without example CRUD")
+	}
+}
+
+// TestGeneratedGoVets type-checks generated Go projects with go vet. It needs
+// the go tool and the generated modules' dependencies, so it is skipped in
+// short mode or when they cannot be downloaded.
+func TestGeneratedGoVets(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("go vet of generated projects skipped in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found in PATH")
+	}
+
+	models := []DataModel{
+		{Name: "user", Fields: []DataField{{Name: "email", Type: "string", Required: true}}},
+		{Name: "post", Fields: []DataField{{Name: "title", Type: "string"}, {Name: "published_at", Type: "datetime", Default: "now"}}, Relations: []DataRelation{
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "author_id"},
+			{Kind: RelationBelongsTo, Model: "user", ForeignKey: "editor_id"},
+		}},
+		{Name: "order", Fields: []DataField{{Name: "total", Type: "float"}}},
+		{Name: "order_item", Fields: []DataField{{Name: "qty", Type: "int"}}, Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "order"}}},
+		// NewPet's types sit next to constructors such as NewOrderRepository.
+		{Name: "new_pet", Fields: []DataField{{Name: "name", Type: "string"}}},
+	}
+	cases := []struct {
+		name string
+		req  GenerateRequest
+	}{
+		{name: "clean gin gorm postgres", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "clean", Database: "postgresql", UseORM: true}},
+		{name: "mvp fiber sql mysql", req: GenerateRequest{Language: "go", Framework: "fiber", Architecture: "mvp", Database: "mysql"}},
+		{name: "hexagonal gin gorm sqlite", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "hexagonal", Database: "sqlite", UseORM: true}},
+		{name: "modular fiber sql postgres", req: GenerateRequest{Language: "go", Framework: "fiber", Architecture: "modular-monolith", Database: "postgresql"}},
+		{name: "microservices gin", req: GenerateRequest{Language: "go", Framework: "gin", Architecture: "microservices", Database: "none", Services: []ServiceConfig{{Name: "billing", Port: 8081}, {Name: "orders", Port: 8082}}}},
+	}
+	engine := testEngine(t)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.req.Custom.Models = models
+			project, err := engine.Build(context.Background(), tc.req)
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			dir := t.TempDir()
+			if err := WriteTree(dir, project.Tree, ConflictOverwrite); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			for _, mod := range goModuleDirs(project.Tree) {
+				run := func(args ...string) ([]byte, error) {
+					cmd := exec.Command(goTool, args...)
+					cmd.Dir = filepath.Join(dir, mod)
+					cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+					return cmd.CombinedOutput()
+				}
+				if out, err := run("mod", "download"); err != nil {
+					t.Skipf("dependencies of %s not available: %v\n%s", mod, err, out)
+				}
+				if out, err := run("vet", "./..."); err != nil {
+					t.Fatalf("go vet %s: %v\n%s", mod, err, out)
+				}
+			}
+		})
+	}
+}
+
+// goModuleDirs lists the folders of tree that hold a go.mod.
+func goModuleDirs(tree FileTree) []string {
+	var out []string
+	for p := range tree.Files {
+		if path.Base(p) == "go.mod" {
+			out = append(out, path.Dir(p))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
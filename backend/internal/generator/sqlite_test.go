@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSQLiteAcrossStacks(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		language  string
+		framework string
+		useORM    bool
+		want      map[string]string
+	}{
+		{name: "go gorm", language: "go", framework: "gin", useORM: true, want: map[string]string{
+			"go.mod":                    "github.com/glebarez/sqlite",
+			"internal/db/connection.go": "conn.Exec(schema)",
+			"internal/db/schema.sql":    "CREATE TABLE IF NOT EXISTS",
+			".env":                      "DATABASE_URL=file:data/app.db?_pragma=foreign_keys=1",
+		}},
+		{name: "go sql", language: "go", framework: "fiber", want: map[string]string{
+			"go.mod":                    "modernc.org/sqlite",
+			"internal/db/connection.go": `sql.Open("sqlite"`,
+			"internal/db/schema.sql":    "CREATE TABLE IF NOT EXISTS",
+			".env":                      "DATABASE_URL=file:data/app.db?_pragma=foreign_keys=1",
+		}},
+		{name: "node prisma", language: "node", framework: "express", useORM: true, want: map[string]string{
+			"prisma/schema.prisma": `provider = "sqlite"`,
+			".env":                 "DATABASE_URL=file:../data/app.db",
+			"package.json":         `"start": "prisma db push --skip-generate && node src/index.js"`,
+		}},
+		{name: "node driver", language: "node", framework: "fastify", want: map[string]string{
+			"src/db/sqlClient.js": "enableForeignKeyConstraints: true",
+		}},
+		{name: "fastapi sqlalchemy", language: "python", framework: "fastapi", useORM: true, want: map[string]string{
+			"requirements.txt":                     "SQLAlchemy==",
+			"app/repository/sqlalchemy_session.py": "check_same_thread",
+			".env":                                 "DATABASE_URL=sqlite:///data/app.db",
+			"app/models_orm.py":                    "Base.metadata.create_all(engine)",
+			"app/db.py":                            "dbapi_connection.execute('PRAGMA foreign_keys = ON')",
+		}},
+		{name: "fastapi driver", language: "python", framework: "fastapi", want: map[string]string{
+			"app/repository/sql_driver.py": "conn.execute('PRAGMA foreign_keys = ON')",
+		}},
+		{name: "django", language: "python", framework: "django", want: map[string]string{
+			"config/settings.py": `BASE_DIR / "data" / "app.db"`,
+			"Dockerfile":         "python manage.py migrate --run-syncdb && python manage.py runserver",
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			project, err := testEngine(t).Build(context.Background(), GenerateRequest{
+				Language:     tc.language,
+				Framework:    tc.framework,
+				Architecture: "mvp",
+				Database:     "sqlite",
+				UseORM:       tc.useORM,
+			})
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			files := project.Tree.Files
+			for p, snippet := range tc.want {
+				if !strings.Contains(files[p], snippet) {
+					t.Fatalf("expected %s to contain %q, got:\n%s", p, snippet, files[p])
+				}
+			}
+			if !strings.Contains(files["migrations/001_initial.sql"], "INTEGER PRIMARY KEY AUTOINCREMENT") {
+				t.Fatalf("expected SQLite DDL, got:\n%s", files["migrations/001_initial.sql"])
+			}
+			if _, ok := files["db/init/001_init.sql"]; ok {
+				t.Fatalf("SQLite has no database container to run init scripts")
+			}
+			compose := files["docker-compose.yaml"]
+			if strings.Contains(compose, "depends_on") || !strings.Contains(compose, "./data:/app/data") {
+				t.Fatalf("expected compose without a DB service and with a data volume, got:\n%s", compose)
+			}
+			if _, ok := project.Tree.Dirs["data"]; !ok {
+				t.Fatalf("expected a data/ folder for the database file")
+			}
+		})
+	}
+}
+
+func TestSQLiteLeavesSchemaToMigrationTool(t *testing.T) {
+	t.Parallel()
+
+	project, err := testEngine(t).Build(context.Background(), GenerateRequest{
+		Language:      "go",
+		Framework:     "gin",
+		Architecture:  "mvp",
+		Database:      "sqlite",
+		UseORM:        true,
+		MigrationTool: MigrateGoose,
+	})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	files := project.Tree.Files
+	if _, ok := files["internal/db/schema.sql"]; ok {
+		t.Fatalf("goose owns the schema, the app should not create tables")
+	}
+	if strings.Contains(files["internal/db/connection.go"], "schema") {
+		t.Fatalf("expected a plain connection, got:\n%s", files["internal/db/connection.go"])
+	}
+}
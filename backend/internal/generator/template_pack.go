@@ -0,0 +1,291 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	CodeInvalidTemplatePack = "invalid_template_pack"
+
+	maxTemplatePackBytes = 8 << 20
+)
+
+// RegisterTemplatePack makes fsys available to requests as
+// template_pack.name. The pack is parsed on top of the engine's templates
+// right away, so a broken pack is rejected at registration.
+func (e *Engine) RegisterTemplatePack(name string, fsys fs.FS) error {
+	name = strings.TrimSpace(name)
+	if !serviceNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid template pack name %q", name)
+	}
+	registry, err := e.registry.WithOverlay(fsys, "pack:"+name)
+	if err != nil {
+		return fmt.Errorf("template pack %s: %w", name, err)
+	}
+	e.packsMu.Lock()
+	defer e.packsMu.Unlock()
+	if e.packs == nil {
+		e.packs = map[string]*TemplateRegistry{}
+	}
+	e.packs[name] = registry
+	return nil
+}
+
+// LoadTemplatePacks registers every sub-folder of dir as a template pack
+// named after the folder.
+func (e *Engine) LoadTemplatePacks(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("template packs are not accessible: %w", err)
+	}
+	var names []string
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if err := e.RegisterTemplatePack(entry.Name(), os.DirFS(filepath.Join(dir, entry.Name()))); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, errors.Join(errs...)
+}
+
+// TemplatePacks lists the registered pack names.
+func (e *Engine) TemplatePacks() []string {
+	e.packsMu.RLock()
+	defer e.packsMu.RUnlock()
+	names := make([]string, 0, len(e.packs))
+	for name := range e.packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// forRequest returns the engine to render req with: e itself, or a copy whose
+// registry has the requested template pack layered over the base templates.
+func (e *Engine) forRequest(req GenerateRequest) (*Engine, error) {
+	ref := req.TemplatePack
+	switch {
+	case ref.Name != "":
+		e.packsMu.RLock()
+		registry, ok := e.packs[ref.Name]
+		e.packsMu.RUnlock()
+		if !ok {
+			hint := "No template packs are registered on this server."
+			if names := e.TemplatePacks(); len(names) > 0 {
+				hint = fmt.Sprintf("Registered packs: %s.", strings.Join(names, ", "))
+			}
+			return nil, &ValidationError{Errors: []FieldError{{
+				Code:    CodeInvalidChoice,
+				Path:    "/template_pack/name",
+				Message: fmt.Sprintf("template pack %q is not registered", ref.Name),
+				Hint:    hint,
+			}}}
+		}
+		return &Engine{registry: registry}, nil
+	case ref.Archive != "":
+		fsys, err := readTemplatePackArchive(ref.Archive)
+		if err != nil {
+			return nil, &ValidationError{Errors: []FieldError{{
+				Code:    CodeInvalidFormat,
+				Path:    "/template_pack/archive",
+				Message: fmt.Sprintf("invalid template pack archive: %v", err),
+				Hint:    "Send a base64-encoded .tar.gz or .zip laid out like backend/templates.",
+			}}}
+		}
+		registry, err := e.registry.WithOverlay(fsys, "pack:upload")
+		if err != nil {
+			return nil, templatePackError(err)
+		}
+		return &Engine{registry: registry}, nil
+	default:
+		return e, nil
+	}
+}
+
+func templatePackError(err error) error {
+	errs := []error{err}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		errs = joined.Unwrap()
+	}
+	verr := &ValidationError{}
+	for _, e := range errs {
+		verr.add(CodeInvalidTemplatePack, "/template_pack/archive", e.Error(),
+			"Every template must parse and every manifest entry must point at an existing template.")
+	}
+	return verr
+}
+
+// readTemplatePackArchive unpacks a base64 .tar.gz or .zip into memory. Only .tmpl
+// and manifest.yaml files are kept, and a single wrapping folder such as
+// "acme-pack/" is stripped so archives of a pack folder work as-is.
+func readTemplatePackArchive(encoded string) (fs.FS, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("not valid base64: %w", err)
+	}
+	files, err := ReadArchive(raw, maxTemplatePackBytes, func(name string) bool {
+		return path.Ext(name) == ".tmpl" || path.Base(name) == manifestFile
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("archive has no .tmpl or manifest.yaml files")
+	}
+
+	prefix := commonTopDir(files)
+	if slices.Contains(languageOptions, strings.TrimSuffix(prefix, "/")) {
+		prefix = ""
+	}
+	out := memFS{}
+	for name, body := range files {
+		out[strings.TrimPrefix(name, prefix)] = []byte(body)
+	}
+	return out, nil
+}
+
+// commonTopDir returns "name/" when every file sits under the same top-level
+// folder, and "" otherwise.
+func commonTopDir(files map[string]string) string {
+	top := ""
+	for name := range files {
+		first, _, ok := strings.Cut(name, "/")
+		if !ok || (top != "" && first != top) {
+			return ""
+		}
+		top = first
+	}
+	if top == "" {
+		return ""
+	}
+	return top + "/"
+}
+
+// memFS is a read-only fs.FS over unpacked archive files keyed by slash path.
+// Directories are implied by the paths of the files below them.
+type memFS map[string][]byte
+
+func (m memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, ok := m[name]; ok {
+		return &memFile{Reader: bytes.NewReader(data), info: memInfo{name: path.Base(name), size: int64(len(data))}}, nil
+	}
+	entries, err := m.ReadDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memDir{info: memInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+func (m memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+	children := map[string]fs.DirEntry{}
+	for p, data := range m {
+		rest, ok := strings.CutPrefix(p, prefix)
+		if !ok {
+			continue
+		}
+		child, _, isDir := strings.Cut(rest, "/")
+		if _, ok := children[child]; ok {
+			continue
+		}
+		info := memInfo{name: child, dir: isDir}
+		if !isDir {
+			info.size = int64(len(data))
+		}
+		children[child] = fs.FileInfoToDirEntry(info)
+	}
+	if len(children) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	out := make([]fs.DirEntry, 0, len(children))
+	for _, e := range children {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+var _ fs.ReadDirFS = memFS{}
+
+type memInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) ModTime() time.Time { return time.Time{} }
+func (i memInfo) IsDir() bool        { return i.dir }
+func (i memInfo) Sys() any           { return nil }
+
+func (i memInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o755
+	}
+	return 0o644
+}
+
+type memFile struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir follows fs.ReadDirFile: n > 0 returns at most n entries and io.EOF
+// once the listing is exhausted.
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	rest = rest[:min(n, len(rest))]
+	d.offset += len(rest)
+	return rest, nil
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func encodeTemplatePack(t *testing.T, files map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatalf("tar write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func templatePackRequest(pack TemplatePackRef) GenerateRequest {
+	return GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "mvp",
+		Database:     "none",
+		Root:         RootOptions{Mode: "new", Name: "pack-demo"},
+		TemplatePack: pack,
+	}
+}
+
+func TestTemplatePackArchiveOverlaysOneGeneration(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	archive := encodeTemplatePack(t, map[string]string{
+		"acme/go/mvp/internal/handlers/ping_handler.tmpl": "// acme ping for {{ .Framework }}\n",
+		"acme/go/mvp/internal/logging/logging.tmpl":       "// acme logging\n",
+		"acme/go/mvp/manifest.yaml": `outputs:
+  - template: cmd/server/main.tmpl
+    output: cmd/server/main.go
+  - template: internal/handlers/ping_handler.tmpl
+    output: internal/handlers/ping_handler.go
+  - template: internal/logging/logging.tmpl
+    output: internal/logging/logging.go
+`,
+		"acme/README.md": "ignored",
+	})
+
+	req := templatePackRequest(TemplatePackRef{Archive: archive})
+	req.FileToggles.ExampleCRUD = boolPtr(false)
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build with pack: %v", err)
+	}
+	if got := project.Tree.Files["internal/handlers/ping_handler.go"]; got != "// acme ping for gin\n" {
+		t.Fatalf("expected pack template to win, got %q", got)
+	}
+	if _, ok := project.Tree.Files["internal/logging/logging.go"]; !ok {
+		t.Fatalf("expected pack manifest to add its own output")
+	}
+	if !strings.Contains(project.Tree.Files["cmd/server/main.go"], "package main") {
+		t.Fatalf("expected embedded fallback for templates the pack does not ship")
+	}
+
+	plain, err := engine.Build(context.Background(), templatePackRequest(TemplatePackRef{}))
+	if err != nil {
+		t.Fatalf("build without pack: %v", err)
+	}
+	if _, ok := plain.Tree.Files["internal/logging/logging.go"]; ok {
+		t.Fatalf("pack must not leak into later generations")
+	}
+}
+
+func TestTemplatePackArchiveRejectsBrokenPacks(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	archive := encodeTemplatePack(t, map[string]string{
+		"go/mvp/broken.tmpl":   "{{ if .Framework }}",
+		"go/mvp/manifest.yaml": "outputs:\n  - template: missing.tmpl\n    output: x.go\n",
+	})
+
+	_, err := engine.Build(context.Background(), templatePackRequest(TemplatePackRef{Archive: archive}))
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if verr.Errors[0].Code != CodeInvalidTemplatePack || !strings.Contains(verr.Error(), "go/mvp/broken.tmpl") {
+		t.Fatalf("expected template pack error naming the broken file, got %+v", verr.Errors)
+	}
+
+	_, err = engine.Build(context.Background(), templatePackRequest(TemplatePackRef{Archive: "not base64!"}))
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/template_pack/archive" {
+		t.Fatalf("expected archive format error, got %v", err)
+	}
+}
+
+func TestRegisteredTemplatePackByName(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	pack := fstest.MapFS{
+		"go/mvp/internal/handlers/ping_handler.tmpl": {Data: []byte("// house ping\n")},
+	}
+	if err := engine.RegisterTemplatePack("house", pack); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := engine.RegisterTemplatePack("broken", fstest.MapFS{"go/mvp/x.tmpl": {Data: []byte("{{ end }}")}}); err == nil {
+		t.Fatalf("expected broken pack to be rejected at registration")
+	}
+
+	req := templatePackRequest(TemplatePackRef{Name: "house"})
+	req.FileToggles.ExampleCRUD = boolPtr(false)
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if got := project.Tree.Files["internal/handlers/ping_handler.go"]; got != "// house ping\n" {
+		t.Fatalf("expected registered pack template, got %q", got)
+	}
+
+	_, err = engine.Build(context.Background(), templatePackRequest(TemplatePackRef{Name: "acme"}))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Code != CodeInvalidChoice || !strings.Contains(verr.Errors[0].Hint, "house") {
+		t.Fatalf("expected unknown pack error listing registered packs, got %v", err)
+	}
+}
+
+func TestMemFSBehavesLikeAFileSystem(t *testing.T) {
+	t.Parallel()
+
+	fsys := memFS{
+		"go/mvp/manifest.yaml":            []byte("files: []\n"),
+		"go/mvp/internal/handlers/a.tmpl": []byte("a"),
+		"go/shared/domain.tmpl":           []byte("domain"),
+		"node/mvp/src/routes/index.tmpl":  []byte(""),
+	}
+	if err := fstest.TestFS(fsys, "go/mvp/manifest.yaml", "go/mvp/internal/handlers/a.tmpl", "go/shared/domain.tmpl", "node/mvp/src/routes/index.tmpl"); err != nil {
+		t.Fatal(err)
+	}
+}
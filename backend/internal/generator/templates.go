@@ -2,35 +2,290 @@ package generator
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"sync"
 	"text/template"
+	"time"
+
+	"stacksprint/backend/templates"
 )
 
 type TemplateRegistry struct {
-	root string
+	fsys        fs.FS
+	overlayRoot string
+	source      string
+
+	mu        sync.RWMutex
+	cache     map[string]*template.Template
+	manifests map[string]ArchManifest
+	hash      string
 }
 
-func NewTemplateRegistry(root string) (*TemplateRegistry, error) {
-	if root == "" {
-		return nil, fmt.Errorf("template root cannot be empty")
+// NewTemplateRegistry serves the embedded templates. When overlayRoot is set,
+// templates found there shadow the embedded ones with the same path.
+func NewTemplateRegistry(overlayRoot string) (*TemplateRegistry, error) {
+	if overlayRoot == "" {
+		return NewTemplateRegistryFS(templates.FS)
 	}
-	if _, err := os.Stat(root); err != nil {
+	info, err := os.Stat(overlayRoot)
+	if err != nil {
 		return nil, fmt.Errorf("template root is not accessible: %w", err)
 	}
-	return &TemplateRegistry{root: root}, nil
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template root %s is not a directory", overlayRoot)
+	}
+	r, err := NewTemplateRegistryFS(overlayFS{upper: os.DirFS(overlayRoot), lower: templates.FS})
+	if err != nil {
+		return nil, err
+	}
+	r.overlayRoot = overlayRoot
+	r.source = "overlay"
+	return r, nil
 }
 
-func (r *TemplateRegistry) Render(path string, data any) (string, error) {
-	fullPath := filepath.Join(r.root, filepath.FromSlash(path))
-	tpl, err := template.ParseFiles(fullPath)
+// NewTemplateRegistryFS parses every .tmpl and manifest.yaml file in fsys up
+// front, so syntax errors and manifests pointing at missing templates fail at
+// startup instead of on the first request that hits them.
+func NewTemplateRegistryFS(fsys fs.FS) (*TemplateRegistry, error) {
+	r := &TemplateRegistry{fsys: fsys, source: "embedded"}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// WithOverlay returns a new registry where templates and manifests in fsys
+// shadow this registry's files. The receiver is left untouched.
+func (r *TemplateRegistry) WithOverlay(fsys fs.FS, source string) (*TemplateRegistry, error) {
+	out, err := NewTemplateRegistryFS(overlayFS{upper: fsys, lower: r.fsys})
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template %s: %w", path, err)
+		return nil, err
+	}
+	out.source = source
+	return out, nil
+}
+
+// Reload re-parses all templates and swaps the cache only if every template
+// parses, so a half-saved file never breaks a running server.
+func (r *TemplateRegistry) Reload() error {
+	cache, err := parseTemplates(r.fsys)
+	if err != nil {
+		return err
+	}
+	manifests, errs := parseManifests(r.fsys, cache)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	hash, err := hashTemplates(r.fsys)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cache = cache
+	r.manifests = manifests
+	r.hash = hash
+	r.mu.Unlock()
+	return nil
+}
+
+func parseTemplates(fsys fs.FS) (map[string]*template.Template, error) {
+	cache := map[string]*template.Template{}
+	var errs []error
+	walkErr := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".tmpl" {
+			return nil
+		}
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read template %s: %w", p, err))
+			return nil
+		}
+		tpl, err := template.New(path.Base(p)).Parse(string(body))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse template %s: %w", p, err))
+			return nil
+		}
+		cache[p] = tpl
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to walk templates: %w", walkErr)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cache, nil
+}
+
+func (r *TemplateRegistry) Render(name string, data any) (string, error) {
+	r.mu.RLock()
+	tpl, ok := r.cache[name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("template %s not found: %w", name, fs.ErrNotExist)
 	}
 	var buf bytes.Buffer
 	if err := tpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to render template %s: %w", path, err)
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
 	}
 	return buf.String(), nil
 }
+
+// Has reports whether a template with the given path was loaded.
+func (r *TemplateRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.cache[name]
+	return ok
+}
+
+// Hash fingerprints every template and manifest currently loaded; it changes
+// whenever any of them does.
+func (r *TemplateRegistry) Hash() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.hash
+}
+
+// Source says where the templates come from: "embedded", "overlay" or
+// "pack:<name>".
+func (r *TemplateRegistry) Source() string {
+	return r.source
+}
+
+func hashTemplates(fsys fs.FS) (string, error) {
+	h := sha256.New()
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (path.Ext(p) != ".tmpl" && path.Base(p) != manifestFile) {
+			return nil
+		}
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", p, len(body))
+		h.Write(body)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash templates: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Manifest returns the architecture manifest for a template folder such as
+// "go/clean".
+func (r *TemplateRegistry) Manifest(dir string) (ArchManifest, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.manifests[dir]
+	return m, ok
+}
+
+// Watch polls the on-disk overlay and reloads the cache whenever a file under
+// it changes. It is meant for local template development and returns when ctx
+// is done; without an overlay there is nothing to watch.
+func (r *TemplateRegistry) Watch(ctx context.Context, interval time.Duration, logf func(format string, args ...any)) {
+	if r.overlayRoot == "" {
+		return
+	}
+	last := snapshotDir(r.overlayRoot)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := snapshotDir(r.overlayRoot)
+			if maps.Equal(last, current) {
+				continue
+			}
+			last = current
+			if err := r.Reload(); err != nil {
+				logf("template reload failed, keeping previous templates: %v", err)
+				continue
+			}
+			logf("templates reloaded from %s", r.overlayRoot)
+		}
+	}
+}
+
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+func snapshotDir(root string) map[string]fileStamp {
+	out := map[string]fileStamp{}
+	_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		out[p] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return out
+}
+
+// overlayFS resolves every path against upper first and falls back to lower.
+// Directory listings are merged so walks see both layers.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if info, err := fs.Stat(o.upper, name); err == nil && !info.IsDir() {
+		return o.upper.Open(name)
+	}
+	f, err := o.lower.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.upper.Open(name)
+	}
+	return f, err
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if upperErr != nil && lowerErr != nil {
+		return nil, lowerErr
+	}
+
+	merged := make(map[string]fs.DirEntry, len(upper)+len(lower))
+	for _, e := range lower {
+		merged[e.Name()] = e
+	}
+	for _, e := range upper {
+		merged[e.Name()] = e
+	}
+	out := make([]fs.DirEntry, 0, len(merged))
+	for _, e := range merged {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out, nil
+}
+
+var _ fs.ReadDirFS = overlayFS{}
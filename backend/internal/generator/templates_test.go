@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateRegistryOverlayShadowsEmbedded(t *testing.T) {
+	t.Parallel()
+
+	embedded, err := NewTemplateRegistry("")
+	if err != nil {
+		t.Fatalf("embedded registry: %v", err)
+	}
+	base, err := embedded.Render("go/mvp/internal/handlers/ping_handler.tmpl", map[string]any{"Framework": "gin"})
+	if err != nil {
+		t.Fatalf("render embedded: %v", err)
+	}
+
+	root := t.TempDir()
+	override := filepath.Join(root, "go", "mvp", "internal", "handlers", "ping_handler.tmpl")
+	if err := os.MkdirAll(filepath.Dir(override), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(override, []byte("// house ping handler for {{ .Framework }}\n"), 0o644); err != nil {
+		t.Fatalf("write override: %v", err)
+	}
+
+	overlay, err := NewTemplateRegistry(root)
+	if err != nil {
+		t.Fatalf("overlay registry: %v", err)
+	}
+	got, err := overlay.Render("go/mvp/internal/handlers/ping_handler.tmpl", map[string]any{"Framework": "gin"})
+	if err != nil {
+		t.Fatalf("render override: %v", err)
+	}
+	if got != "// house ping handler for gin\n" || got == base {
+		t.Fatalf("expected overlay template to win, got %q", got)
+	}
+
+	other, err := overlay.Render("go/shared/domain_errors.tmpl", map[string]any{"Framework": "gin"})
+	if err != nil {
+		t.Fatalf("expected embedded fallback for templates missing from overlay: %v", err)
+	}
+	if !strings.Contains(other, "package domain") {
+		t.Fatalf("unexpected fallback render: %q", other)
+	}
+}
+
+func TestNewTemplateRegistryRejectsMissingOverlay(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTemplateRegistry(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatalf("expected error for missing overlay root")
+	}
+}
+
+func TestNewTemplateRegistryFailsFastOnSyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	broken := filepath.Join(root, "go", "mvp", "broken.tmpl")
+	if err := os.MkdirAll(filepath.Dir(broken), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(broken, []byte("{{ if .Framework }}unterminated"), 0o644); err != nil {
+		t.Fatalf("write broken template: %v", err)
+	}
+
+	_, err := NewTemplateRegistry(root)
+	if err == nil || !strings.Contains(err.Error(), "go/mvp/broken.tmpl") {
+		t.Fatalf("expected startup parse error naming the template, got %v", err)
+	}
+}
+
+func TestTemplateRegistryWatchReloadsOverlay(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	tplPath := filepath.Join(root, "go", "mvp", "internal", "handlers", "ping_handler.tmpl")
+	if err := os.MkdirAll(filepath.Dir(tplPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(tplPath, []byte("v1"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	reg, err := NewTemplateRegistry(root)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan struct{}, 1)
+	go reg.Watch(ctx, 10*time.Millisecond, func(format string, _ ...any) {
+		if strings.HasPrefix(format, "templates reloaded") {
+			select {
+			case reloaded <- struct{}{}:
+			default:
+			}
+		}
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(tplPath, []byte("version two"), 0o644); err != nil {
+		t.Fatalf("rewrite template: %v", err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected watcher to reload changed template")
+	}
+
+	got, err := reg.Render("go/mvp/internal/handlers/ping_handler.tmpl", nil)
+	if err != nil || got != "version two" {
+		t.Fatalf("expected reloaded template, got %q (%v)", got, err)
+	}
+}
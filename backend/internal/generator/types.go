@@ -13,6 +13,10 @@ type GenerateRequest struct {
 	Custom               CustomOptions     `json:"custom"`
 	Root                 RootOptions       `json:"root"`
 	ServiceCommunication string            `json:"service_communication"`
+	TemplatePack         TemplatePackRef   `json:"template_pack"`
+	// MigrationTool replaces the plain SQL migration with a tool's config,
+	// numbered migrations and a one-shot compose service.
+	MigrationTool string `json:"migration_tool,omitempty"`
 }
 
 type ServiceConfig struct {
@@ -51,27 +55,63 @@ type FileToggleOptions struct {
 }
 
 type CustomOptions struct {
-	AddFolders      []string     `json:"add_folders"`
-	AddFiles        []CustomFile `json:"add_files"`
-	Models          []DataModel  `json:"models"`
-	AddServiceNames []string     `json:"add_service_names"`
-	RemoveFolders   []string     `json:"remove_folders"`
-	RemoveFiles     []string     `json:"remove_files"`
+	AddFolders []string     `json:"add_folders"`
+	AddFiles   []CustomFile `json:"add_files"`
+	Models     []DataModel  `json:"models"`
+	// DDL holds CREATE TABLE statements whose tables are appended to Models,
+	// see ParseDDL.
+	DDL string `json:"ddl,omitempty"`
+	// OpenAPI holds an OpenAPI 3 document in YAML or JSON. Its component
+	// schemas are appended to Models and its paths become handler stubs, see
+	// ParseOpenAPI.
+	OpenAPI         string   `json:"openapi,omitempty"`
+	AddServiceNames []string `json:"add_service_names"`
+	RemoveFolders   []string `json:"remove_folders"`
+	RemoveFiles     []string `json:"remove_files"`
 }
 
 type DataModel struct {
-	Name   string      `json:"name"`
-	Fields []DataField `json:"fields"`
+	Name      string         `json:"name"`
+	Fields    []DataField    `json:"fields"`
+	Relations []DataRelation `json:"relations,omitempty"`
 }
 
+// DataRelation links a model to another entry of custom.models. belongs_to
+// puts ForeignKey on this model, has_many puts it on Model, and many_to_many
+// adds JoinTable. The opposite side is derived, so declare each link once.
+type DataRelation struct {
+	Kind       string `json:"kind"`
+	Model      string `json:"model"`
+	ForeignKey string `json:"foreign_key,omitempty"`
+	JoinTable  string `json:"join_table,omitempty"`
+}
+
+// DataField is a column of a custom model. Columns are nullable unless
+// Required is set or Nullable is false; Required also makes the field
+// mandatory in request validation. Default is a literal of the field type,
+// or "now" for datetime fields. Scale defaults to 2 when Precision is set.
 type DataField struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Required  bool     `json:"required,omitempty"`
+	Nullable  *bool    `json:"nullable,omitempty"`
+	Unique    bool     `json:"unique,omitempty"`
+	Default   string   `json:"default,omitempty"`
+	MaxLength int      `json:"max_length,omitempty"`
+	Precision int      `json:"precision,omitempty"`
+	Scale     *int     `json:"scale,omitempty"`
+	Index     bool     `json:"index,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
 }
 
+// CustomFile adds or merges a file. Mode decides how Content is combined with
+// a generated file at the same path; when empty, .json and .yaml files are
+// merged and anything else is appended.
 type CustomFile struct {
 	Path    string `json:"path"`
 	Content string `json:"content"`
+	Mode    string `json:"mode,omitempty"`
+	Anchor  string `json:"anchor,omitempty"`
 }
 
 type RootOptions struct {
@@ -80,6 +120,16 @@ type RootOptions struct {
 	Path    string `json:"path"`
 	GitInit bool   `json:"git_init"`
 	Module  string `json:"module"`
+	// OnConflict decides what existing-mode scripts do with files that
+	// already exist: overwrite (default), skip, backup or fail.
+	OnConflict string `json:"on_conflict"`
+}
+
+// TemplatePackRef selects a template pack for one generation: either a pack
+// registered on the server by Name, or an inline base64-encoded .tar.gz or .zip.
+type TemplatePackRef struct {
+	Name    string `json:"name,omitempty"`
+	Archive string `json:"archive,omitempty"`
 }
 
 type GenerateResponse struct {
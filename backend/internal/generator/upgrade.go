@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+const (
+	UpgradeAdded     = "added"
+	UpgradeUpdated   = "updated"
+	UpgradeMerged    = "merged"
+	UpgradeDeleted   = "deleted"
+	UpgradeConflict  = "conflict"
+	UpgradeKept      = "kept"
+	UpgradeUnchanged = "unchanged"
+)
+
+// UpgradeRequest carries everything needed to move a generated project onto
+// the current templates. Previous is the file set StackSprint generated
+// originally, Current the project as it is today (path -> content). When
+// Request is empty it is read from Current[".stacksprint.json"].
+type UpgradeRequest struct {
+	Request  *GenerateRequest  `json:"request"`
+	Previous map[string]string `json:"previous"`
+	Current  map[string]string `json:"current"`
+}
+
+// UpgradeFile is the outcome for one path. Content is the file to write for
+// added, updated, merged and conflict results; deleted files should be
+// removed, and kept/unchanged files left alone.
+type UpgradeFile struct {
+	Path      string          `json:"path"`
+	Status    string          `json:"status"`
+	Reason    string          `json:"reason,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	Conflicts []MergeConflict `json:"conflicts,omitempty"`
+}
+
+type UpgradeResult struct {
+	Files     []UpgradeFile `json:"files"`
+	Conflicts int           `json:"conflicts"`
+	Warnings  []string      `json:"warnings"`
+}
+
+// Upgrade regenerates the project with the engine's current templates and
+// three-way merges every generated file: the previous output is the common
+// base, the current project holds the user's edits and the new output holds
+// the template changes. Files that only exist in Current are not touched.
+func (e *Engine) Upgrade(ctx context.Context, up UpgradeRequest) (UpgradeResult, error) {
+	var req GenerateRequest
+	switch {
+	case up.Request != nil:
+		req = *up.Request
+	case up.Current[ProjectManifestPath] != "":
+		manifest, err := ParseProjectManifest([]byte(up.Current[ProjectManifestPath]))
+		if err != nil {
+			return UpgradeResult{}, &ValidationError{Errors: []FieldError{{
+				Code:    CodeInvalidFormat,
+				Path:    "/current/" + escapeJSONPointer(ProjectManifestPath),
+				Message: err.Error(),
+				Hint:    "Send the original request in request instead.",
+			}}}
+		}
+		req = manifest.Request
+		if hash := manifest.Templates.ArchiveHash; hash != "" {
+			return UpgradeResult{}, &ValidationError{Errors: []FieldError{{
+				Code:    CodeRequired,
+				Path:    "/request/template_pack/archive",
+				Message: fmt.Sprintf("the project was generated with an uploaded template pack (%s) that %s does not store", hash, ProjectManifestPath),
+				Hint:    "Send the original request in request, including template_pack.archive.",
+			}}}
+		}
+	default:
+		return UpgradeResult{}, &ValidationError{Errors: []FieldError{{
+			Code:    CodeRequired,
+			Path:    "/request",
+			Message: "request is required when current has no " + ProjectManifestPath,
+			Hint:    "Send the GenerateRequest the project was generated from.",
+		}}}
+	}
+
+	project, err := e.Build(ctx, req)
+	if err != nil {
+		if up.Request != nil {
+			err = prefixValidationPaths(err, "/request")
+		}
+		return UpgradeResult{}, err
+	}
+	result := MergeUpgrade(up.Previous, up.Current, project.Tree.Files)
+	result.Warnings = project.Warnings()
+	return result, nil
+}
+
+// MergeUpgrade decides, path by path, how to combine the previous generated
+// output, the current project and the newly generated output.
+func MergeUpgrade(previous, current, generated map[string]string) UpgradeResult {
+	paths := map[string]struct{}{}
+	for p := range previous {
+		paths[p] = struct{}{}
+	}
+	for p := range generated {
+		paths[p] = struct{}{}
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	result := UpgradeResult{Files: make([]UpgradeFile, 0, len(sorted))}
+	for _, p := range sorted {
+		base, inBase := previous[p]
+		cur, inCur := current[p]
+		next, inNext := generated[p]
+		file := mergeUpgradeFile(p, base, inBase, cur, inCur, next, inNext)
+		if file.Status == UpgradeConflict {
+			result.Conflicts++
+		}
+		result.Files = append(result.Files, file)
+	}
+	return result
+}
+
+func mergeUpgradeFile(p, base string, inBase bool, cur string, inCur bool, next string, inNext bool) UpgradeFile {
+	file := UpgradeFile{Path: p}
+	switch {
+	case !inNext:
+		switch {
+		case !inCur:
+			file.Status = UpgradeUnchanged
+		case cur == base:
+			file.Status = UpgradeDeleted
+			file.Reason = "no longer generated"
+		default:
+			file.Status = UpgradeConflict
+			file.Reason = "no longer generated but modified locally; kept the local file"
+		}
+	case !inCur:
+		switch {
+		case !inBase:
+			file.Status, file.Content = UpgradeAdded, next
+		case base == next:
+			file.Status = UpgradeKept
+			file.Reason = "deleted locally"
+		default:
+			file.Status, file.Content = UpgradeConflict, next
+			file.Reason = "deleted locally but changed by the new templates"
+		}
+	case cur == next:
+		file.Status = UpgradeUnchanged
+	case inBase && cur == base:
+		file.Status, file.Content = UpgradeUpdated, next
+	case inBase && base == next:
+		file.Status = UpgradeKept
+		file.Reason = "modified locally; templates unchanged"
+	default:
+		merged, conflicts := merge3(base, cur, next, "current", "stacksprint")
+		file.Content = merged
+		file.Status = UpgradeMerged
+		if len(conflicts) > 0 {
+			file.Status = UpgradeConflict
+			file.Conflicts = conflicts
+			file.Reason = "local and template changes overlap"
+		}
+	}
+	return file
+}
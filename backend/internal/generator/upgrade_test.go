@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	t.Parallel()
+
+	base := "a\nb\nc\nd\ne\n"
+	cases := []struct {
+		name      string
+		ours      string
+		theirs    string
+		want      string
+		conflicts int
+	}{
+		{name: "only theirs", ours: base, theirs: "a\nB\nc\nd\ne\n", want: "a\nB\nc\nd\ne\n"},
+		{name: "only ours", ours: "a\nb\nc\nd\ne\nf\n", theirs: base, want: "a\nb\nc\nd\ne\nf\n"},
+		{name: "disjoint edits", ours: "A\nb\nc\nd\ne\n", theirs: "a\nb\nc\nd\nE\n", want: "A\nb\nc\nd\nE\n"},
+		{name: "same edit", ours: "a\nX\nc\nd\ne\n", theirs: "a\nX\nc\nd\ne\n", want: "a\nX\nc\nd\ne\n"},
+		{name: "insert and delete", ours: "a\nb\nnew\nc\nd\ne\n", theirs: "a\nb\nc\ne\n", want: "a\nb\nnew\nc\ne\n"},
+		{
+			name:      "overlap",
+			ours:      "a\nmine\nc\nd\ne\n",
+			theirs:    "a\ntheirs\nc\nd\ne\n",
+			want:      "a\n<<<<<<< current\nmine\n=======\ntheirs\n>>>>>>> stacksprint\nc\nd\ne\n",
+			conflicts: 1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, conflicts := merge3(base, tc.ours, tc.theirs, "current", "stacksprint")
+			if got != tc.want {
+				t.Fatalf("merge mismatch:\n got %q\nwant %q", got, tc.want)
+			}
+			if len(conflicts) != tc.conflicts {
+				t.Fatalf("expected %d conflicts, got %+v", tc.conflicts, conflicts)
+			}
+			if tc.conflicts > 0 && (conflicts[0].StartLine != 2 || conflicts[0].EndLine != 6) {
+				t.Fatalf("unexpected conflict location %+v", conflicts[0])
+			}
+		})
+	}
+}
+
+func TestUpgradeMergesTemplateChangesWithLocalEdits(t *testing.T) {
+	t.Parallel()
+
+	engine := testEngine(t)
+	req := GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "mvp",
+		Database:     "none",
+		Root:         RootOptions{Mode: "new", Name: "upgrade-demo"},
+	}
+	project, err := engine.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	generated := project.Tree.Files
+
+	// Pretend the previous templates produced slightly different output.
+	previous := map[string]string{}
+	for p, content := range generated {
+		previous[p] = content
+	}
+	previous["Dockerfile"] = "# old base image\n" + generated["Dockerfile"]
+	previous["internal/legacy.go"] = "package internal\n"
+	previous["README.md"] = "old readme\n"
+
+	current := map[string]string{}
+	for p, content := range previous {
+		current[p] = content
+	}
+	current["Dockerfile"] = previous["Dockerfile"] + "# local tweak\n"
+	current["README.md"] = "our readme\n"
+	current["internal/extra.go"] = "package internal\n"
+	delete(current, "go.mod")
+
+	result, err := engine.Upgrade(context.Background(), UpgradeRequest{Previous: previous, Current: current})
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+
+	byPath := map[string]UpgradeFile{}
+	for _, f := range result.Files {
+		byPath[f.Path] = f
+	}
+	if f := byPath["Dockerfile"]; f.Status != UpgradeMerged || f.Content != generated["Dockerfile"]+"# local tweak\n" {
+		t.Fatalf("expected clean merge of Dockerfile, got %+v", f)
+	}
+	if f := byPath["internal/legacy.go"]; f.Status != UpgradeDeleted {
+		t.Fatalf("expected stale generated file to be deleted, got %+v", f)
+	}
+	if f := byPath["README.md"]; f.Status != UpgradeConflict || !strings.Contains(f.Content, "<<<<<<< current") || len(f.Conflicts) != 1 {
+		t.Fatalf("expected README conflict, got %+v", f)
+	}
+	if f := byPath["go.mod"]; f.Status != UpgradeKept {
+		t.Fatalf("expected locally deleted, unchanged file to stay deleted, got %+v", f)
+	}
+	if f := byPath["cmd/server/main.go"]; f.Status != UpgradeUnchanged {
+		t.Fatalf("expected untouched file to be unchanged, got %+v", f)
+	}
+	if _, ok := byPath["internal/extra.go"]; ok {
+		t.Fatalf("user-only files must not be reported")
+	}
+	if result.Conflicts != 1 {
+		t.Fatalf("expected one conflict, got %d", result.Conflicts)
+	}
+}
+
+func TestUpgradeRequiresRequestOrManifest(t *testing.T) {
+	t.Parallel()
+
+	_, err := testEngine(t).Upgrade(context.Background(), UpgradeRequest{Current: map[string]string{"main.go": ""}})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/request" {
+		t.Fatalf("expected missing request error, got %v", err)
+	}
+
+	_, err = testEngine(t).Upgrade(context.Background(), UpgradeRequest{Request: &GenerateRequest{Language: "cobol"}})
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/request/language" {
+		t.Fatalf("expected request errors to point into /request, got %v", err)
+	}
+
+	manifest := `{"schema_version": 1, "templates": {"archive_hash": "sha256:abc"}, "request": {"language": "go"}}`
+	_, err = testEngine(t).Upgrade(context.Background(), UpgradeRequest{Current: map[string]string{ProjectManifestPath: manifest}})
+	if !errors.As(err, &verr) || verr.Errors[0].Path != "/request/template_pack/archive" || !strings.Contains(verr.Error(), "sha256:abc") {
+		t.Fatalf("expected the uploaded pack to be asked for, got %v", err)
+	}
+}
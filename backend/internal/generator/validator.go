@@ -5,16 +5,25 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 var (
-	allowedLanguages     = map[string]struct{}{"go": {}, "node": {}, "python": {}}
-	allowedArchitectures = map[string]struct{}{
-		"mvp": {}, "clean": {}, "hexagonal": {}, "modular-monolith": {}, "microservices": {},
-	}
-	allowedDBs          = map[string]struct{}{"postgresql": {}, "mysql": {}, "mongodb": {}, "none": {}}
-	frameworkByLanguage = map[string]map[string]struct{}{
+	languageOptions      = []string{"go", "node", "python"}
+	architectureOptions  = []string{"mvp", "clean", "hexagonal", "modular-monolith", "microservices"}
+	databaseOptions      = []string{"postgresql", "mysql", "sqlite", "mongodb", "none"}
+	rootModeOptions      = []string{"new", "existing"}
+	communicationOptions = []string{"none", "http", "grpc"}
+	conflictOptions      = []string{ConflictOverwrite, ConflictSkip, ConflictBackup, ConflictFail}
+
+	allowedLanguages     = setOf(languageOptions...)
+	allowedArchitectures = setOf(architectureOptions...)
+	allowedDBs           = setOf(databaseOptions...)
+	frameworkByLanguage  = map[string]map[string]struct{}{
 		"go":     {"gin": {}, "fiber": {}},
 		"node":   {"express": {}, "fastify": {}},
 		"python": {"fastapi": {}, "django": {}},
@@ -22,82 +31,242 @@ var (
 	serviceNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
 )
 
+const (
+	minMicroservices = 2
+	maxMicroservices = 5
+)
+
+const (
+	CodeInvalidChoice = "invalid_choice"
+	CodeRequired      = "required"
+	CodeInvalidFormat = "invalid_format"
+	CodeDuplicate     = "duplicate"
+	CodeOutOfRange    = "out_of_range"
+	CodeInvalidPath   = "invalid_path"
+)
+
+// FieldError describes a single invalid request field. Path is a JSON pointer
+// into the request body, e.g. /services/1/port.
+type FieldError struct {
+	Code    string `json:"code"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// ValidationError carries every violation found in a request.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(code, path, message, hint string) {
+	e.Errors = append(e.Errors, FieldError{Code: code, Path: path, Message: message, Hint: hint})
+}
+
 func Validate(req GenerateRequest) error {
+	verr := &ValidationError{}
+
 	lang := strings.ToLower(strings.TrimSpace(req.Language))
 	if _, ok := allowedLanguages[lang]; !ok {
-		return errors.New("language must be one of: go, node, python")
-	}
-
-	fw := strings.ToLower(strings.TrimSpace(req.Framework))
-	if _, ok := frameworkByLanguage[lang][fw]; !ok {
-		return fmt.Errorf("framework %q is not valid for %s", req.Framework, req.Language)
+		verr.add(CodeInvalidChoice, "/language",
+			fmt.Sprintf("language must be one of: %s", strings.Join(languageOptions, ", ")),
+			"Pick a supported language.")
+	} else {
+		fw := strings.ToLower(strings.TrimSpace(req.Framework))
+		if _, ok := frameworkByLanguage[lang][fw]; !ok {
+			verr.add(CodeInvalidChoice, "/framework",
+				fmt.Sprintf("framework %q is not valid for %s", req.Framework, req.Language),
+				fmt.Sprintf("Use one of: %s.", strings.Join(frameworksFor(lang), ", ")))
+		}
 	}
 
 	arch := strings.ToLower(strings.TrimSpace(req.Architecture))
 	if _, ok := allowedArchitectures[arch]; !ok {
-		return errors.New("architecture must be one of: mvp, clean, hexagonal, modular-monolith, microservices")
+		verr.add(CodeInvalidChoice, "/architecture",
+			fmt.Sprintf("architecture must be one of: %s", strings.Join(architectureOptions, ", ")),
+			"Pick a supported architecture.")
 	}
 
 	db := strings.ToLower(strings.TrimSpace(req.Database))
 	if _, ok := allowedDBs[db]; !ok {
-		return errors.New("db must be one of: postgresql, mysql, mongodb, none")
+		verr.add(CodeInvalidChoice, "/db",
+			fmt.Sprintf("db must be one of: %s", strings.Join(databaseOptions, ", ")),
+			"Use \"none\" to skip database boilerplate.")
 	}
 
+	validateMigrationTool(verr, req)
+
 	if arch == "microservices" {
-		if len(req.Services) < 2 || len(req.Services) > 5 {
-			return errors.New("microservices mode requires 2 to 5 services")
+		if len(req.Services) < minMicroservices || len(req.Services) > maxMicroservices {
+			verr.add(CodeOutOfRange, "/services",
+				fmt.Sprintf("microservices mode requires %d to %d services", minMicroservices, maxMicroservices),
+				"Add or remove services to fit the supported range.")
 		}
 		seen := map[string]struct{}{}
 		for i, svc := range req.Services {
 			name := strings.TrimSpace(svc.Name)
 			if !serviceNameRegex.MatchString(name) {
-				return fmt.Errorf("services[%d].name is invalid", i)
-			}
-			if _, ok := seen[strings.ToLower(name)]; ok {
-				return fmt.Errorf("duplicate service name %q", name)
+				verr.add(CodeInvalidFormat, fmt.Sprintf("/services/%d/name", i),
+					fmt.Sprintf("services[%d].name is invalid", i),
+					"Start with a letter and use only letters, digits, '-' or '_'.")
+			} else if _, ok := seen[strings.ToLower(name)]; ok {
+				verr.add(CodeDuplicate, fmt.Sprintf("/services/%d/name", i),
+					fmt.Sprintf("duplicate service name %q", name),
+					"Service names must be unique (case-insensitive).")
 			}
 			seen[strings.ToLower(name)] = struct{}{}
-			if svc.Port <= 0 {
-				return fmt.Errorf("services[%d].port must be a positive number", i)
+			if svc.Port <= 0 || svc.Port > 65535 {
+				verr.add(CodeOutOfRange, fmt.Sprintf("/services/%d/port", i),
+					fmt.Sprintf("services[%d].port must be between 1 and 65535", i),
+					"Pick an unused TCP port such as 8081.")
 			}
 		}
 	}
 
 	rootMode := strings.ToLower(strings.TrimSpace(req.Root.Mode))
-	if rootMode != "new" && rootMode != "existing" {
-		return errors.New("root.mode must be either 'new' or 'existing'")
+	if !slices.Contains(rootModeOptions, rootMode) {
+		verr.add(CodeInvalidChoice, "/root/mode",
+			"root.mode must be either 'new' or 'existing'",
+			"Use 'new' to create a folder or 'existing' to write into one.")
 	}
 	if rootMode == "new" && strings.TrimSpace(req.Root.Name) == "" {
-		return errors.New("root.name is required when root.mode is 'new'")
+		verr.add(CodeRequired, "/root/name", "root.name is required when root.mode is 'new'", "Name the project folder.")
 	}
 	if rootMode == "existing" && strings.TrimSpace(req.Root.Path) == "" {
-		return errors.New("root.path is required when root.mode is 'existing'")
+		verr.add(CodeRequired, "/root/path", "root.path is required when root.mode is 'existing'", "Point at the existing project folder.")
+	}
+	if req.Root.OnConflict != "" && !slices.Contains(conflictOptions, req.Root.OnConflict) {
+		verr.add(CodeInvalidChoice, "/root/on_conflict",
+			fmt.Sprintf("root.on_conflict must be one of: %s", strings.Join(conflictOptions, ", ")),
+			"Pick how existing files are handled in existing mode.")
+	}
+	if req.TemplatePack.Name != "" && req.TemplatePack.Archive != "" {
+		verr.add(CodeInvalidFormat, "/template_pack", "template_pack takes either name or archive, not both", "Drop one of template_pack.name or template_pack.archive.")
 	}
 
-	for _, p := range req.Custom.AddFolders {
+	const relPathHint = "Use a relative path without '..' or a leading '/'."
+	for i, p := range req.Custom.AddFolders {
 		if err := validateRelPath(p); err != nil {
-			return fmt.Errorf("invalid custom folder %q: %w", p, err)
+			verr.add(CodeInvalidPath, fmt.Sprintf("/custom/add_folders/%d", i), fmt.Sprintf("invalid custom folder %q: %v", p, err), relPathHint)
 		}
 	}
-	for _, f := range req.Custom.AddFiles {
+	for i, f := range req.Custom.AddFiles {
 		if err := validateRelPath(f.Path); err != nil {
-			return fmt.Errorf("invalid custom file path %q: %w", f.Path, err)
+			verr.add(CodeInvalidPath, fmt.Sprintf("/custom/add_files/%d/path", i), fmt.Sprintf("invalid custom file path %q: %v", f.Path, err), relPathHint)
 		}
+		validateCustomFileMode(verr, i, f)
+	}
+	validateModelNames(verr, req.Custom.Models)
+	validateModelFields(verr, req.Custom.Models)
+	validateModelRelations(verr, req.Custom.Models)
+	validateReservedNames(verr, lang, db, req.Custom.Models)
+	if lang == "go" {
+		validateGoModelNames(verr, req.Custom.Models)
 	}
-	for _, p := range req.Custom.RemoveFolders {
+	for i, p := range req.Custom.RemoveFolders {
 		if err := validateRelPath(p); err != nil {
-			return fmt.Errorf("invalid remove folder %q: %w", p, err)
+			verr.add(CodeInvalidPath, fmt.Sprintf("/custom/remove_folders/%d", i), fmt.Sprintf("invalid remove folder %q: %v", p, err), relPathHint)
 		}
 	}
-	for _, p := range req.Custom.RemoveFiles {
+	for i, p := range req.Custom.RemoveFiles {
 		if err := validateRelPath(p); err != nil {
-			return fmt.Errorf("invalid remove file %q: %w", p, err)
+			verr.add(CodeInvalidPath, fmt.Sprintf("/custom/remove_files/%d", i), fmt.Sprintf("invalid remove file %q: %v", p, err), relPathHint)
 		}
 	}
 
+	if len(verr.Errors) > 0 {
+		return verr
+	}
 	return nil
 }
 
+// validateModelNames rejects models, and fields of one model, whose names
+// collide once turned into type and struct field names, e.g. user and User.
+func validateModelNames(verr *ValidationError, models []DataModel) {
+	seen := map[string]struct{}{}
+	for i, m := range models {
+		name := toPascal(m.Name)
+		if _, ok := seen[name]; ok && strings.TrimSpace(m.Name) != "" {
+			verr.add(CodeDuplicate, fmt.Sprintf("/custom/models/%d/name", i),
+				fmt.Sprintf("duplicate model name %q", m.Name),
+				"Model names must be unique (case-insensitive).")
+		}
+		seen[name] = struct{}{}
+		fields := map[string]struct{}{}
+		for j, f := range m.Fields {
+			field := toPascal(f.Name)
+			if _, ok := fields[field]; ok && strings.TrimSpace(f.Name) != "" {
+				verr.add(CodeDuplicate, fmt.Sprintf("/custom/models/%d/fields/%d/name", i, j),
+					fmt.Sprintf("duplicate field name %q in model %q", f.Name, m.Name),
+					"Field names must be unique within a model (case-insensitive).")
+			}
+			fields[field] = struct{}{}
+		}
+	}
+}
+
+// validateGoModelNames rejects a model named New<Model> next to <Model>: its
+// types, e.g. NewPetRepository, are the other model's constructors.
+func validateGoModelNames(verr *ValidationError, models []DataModel) {
+	names := map[string]string{}
+	for _, m := range models {
+		if name := strings.TrimSpace(m.Name); name != "" {
+			names[toPascal(name)] = name
+		}
+	}
+	for i, m := range models {
+		pascal := toPascal(m.Name)
+		other, ok := names[strings.TrimPrefix(pascal, "New")]
+		if !strings.HasPrefix(pascal, "New") || !ok {
+			continue
+		}
+		verr.add(CodeDuplicate, fmt.Sprintf("/custom/models/%d/name", i),
+			fmt.Sprintf("model %q clashes with the Go constructors of model %q, e.g. %sRepository", m.Name, other, pascal),
+			"Rename the model, e.g. to "+pascal[len("New"):]+"Draft.")
+	}
+}
+
+func validateCustomFileMode(verr *ValidationError, i int, f CustomFile) {
+	ptr := fmt.Sprintf("/custom/add_files/%d", i)
+	switch f.Mode {
+	case "", CustomReplace, CustomAppend, CustomPrepend:
+	case CustomInsertAtAnchor:
+		if strings.TrimSpace(f.Anchor) == "" {
+			verr.add(CodeRequired, ptr+"/anchor", "anchor is required for mode insert-at-anchor", "Set anchor to a line of the generated file to insert after.")
+		}
+	case CustomJSONMerge:
+		if _, err := parseOrderedJSON(f.Content); err != nil {
+			verr.add(CodeInvalidJSON, ptr+"/content", fmt.Sprintf("content is not valid JSON: %v", err), "json-merge needs a JSON document, usually an object.")
+		}
+	case CustomYAMLMerge:
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(f.Content), &node); err != nil {
+			verr.add(CodeInvalidFormat, ptr+"/content", fmt.Sprintf("content is not valid YAML: %v", err), "yaml-merge needs a YAML document, usually a mapping.")
+		}
+	default:
+		verr.add(CodeInvalidChoice, ptr+"/mode",
+			fmt.Sprintf("custom file mode must be one of: %s", strings.Join(customFileModes, ", ")),
+			"Leave mode empty to append, or merge .json/.yaml files.")
+	}
+}
+
+func frameworksFor(lang string) []string {
+	out := make([]string, 0, len(frameworkByLanguage[lang]))
+	for fw := range frameworkByLanguage[lang] {
+		out = append(out, fw)
+	}
+	sort.Strings(out)
+	return out
+}
+
 func validateRelPath(p string) error {
 	p = filepath.ToSlash(strings.TrimSpace(p))
 	if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, "..") {
@@ -117,6 +286,14 @@ func archTemplateName(arch string) string {
 	return a
 }
 
+func setOf(values ...string) map[string]struct{} {
+	out := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		out[v] = struct{}{}
+	}
+	return out
+}
+
 func isEnabled(flag *bool) bool {
 	if flag == nil {
 		return true
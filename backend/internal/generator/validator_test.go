@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	t.Parallel()
+
+	err := Validate(GenerateRequest{
+		Language:     "go",
+		Framework:    "express",
+		Architecture: "microservices",
+		Database:     "oracle",
+		Services: []ServiceConfig{
+			{Name: "users", Port: 8081},
+			{Name: "1bad", Port: 0},
+		},
+		Root: RootOptions{Mode: "existing"},
+		Custom: CustomOptions{
+			AddFiles: []CustomFile{{Path: "../escape.txt"}},
+		},
+	})
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %T (%v)", err, err)
+	}
+
+	want := map[string]string{
+		"/framework":               CodeInvalidChoice,
+		"/db":                      CodeInvalidChoice,
+		"/services/1/name":         CodeInvalidFormat,
+		"/services/1/port":         CodeOutOfRange,
+		"/root/path":               CodeRequired,
+		"/custom/add_files/0/path": CodeInvalidPath,
+	}
+	got := map[string]FieldError{}
+	for _, fe := range verr.Errors {
+		got[fe.Path] = fe
+	}
+	for path, code := range want {
+		fe, ok := got[path]
+		if !ok {
+			t.Fatalf("expected violation at %s, got %+v", path, verr.Errors)
+		}
+		if fe.Code != code {
+			t.Fatalf("expected code %s at %s, got %s", code, path, fe.Code)
+		}
+		if fe.Hint == "" || fe.Message == "" {
+			t.Fatalf("expected message and hint at %s, got %+v", path, fe)
+		}
+	}
+	if len(verr.Errors) != len(want) {
+		t.Fatalf("expected %d violations, got %d: %+v", len(want), len(verr.Errors), verr.Errors)
+	}
+}
+
+func TestValidateAcceptsValidRequest(t *testing.T) {
+	t.Parallel()
+
+	err := Validate(GenerateRequest{
+		Language:     "python",
+		Framework:    "fastapi",
+		Architecture: "mvp",
+		Database:     "none",
+		Root:         RootOptions{Mode: "new", Name: "ok"},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
+
+func TestValidateRejectsGoModelNamedAfterConstructor(t *testing.T) {
+	t.Parallel()
+
+	req := GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "clean",
+		Database:     "none",
+		Root:         RootOptions{Mode: "new", Name: "ok"},
+		Custom: CustomOptions{Models: []DataModel{
+			{Name: "pet", Fields: []DataField{{Name: "name", Type: "string"}}},
+			{Name: "new_pet", Fields: []DataField{{Name: "name", Type: "string"}}},
+		}},
+	}
+	var verr *ValidationError
+	if err := Validate(req); !errors.As(err, &verr) || len(verr.Errors) != 1 || verr.Errors[0].Path != "/custom/models/1/name" || verr.Errors[0].Code != CodeDuplicate {
+		t.Fatalf("expected a duplicate error at /custom/models/1/name, got %v", err)
+	}
+
+	req.Language, req.Framework = "node", "express"
+	if err := Validate(req); err != nil {
+		t.Fatalf("node has no New* constructors, got %v", err)
+	}
+}
+
+func TestValidateRejectsReservedFieldNames(t *testing.T) {
+	t.Parallel()
+
+	req := GenerateRequest{
+		Language:     "python",
+		Framework:    "fastapi",
+		Architecture: "mvp",
+		Database:     "postgresql",
+		Root:         RootOptions{Mode: "new", Name: "ok"},
+		Custom: CustomOptions{Models: []DataModel{
+			{Name: "user", Fields: []DataField{{Name: "email", Type: "string"}}},
+			{Name: "lesson", Fields: []DataField{{Name: "class", Type: "string"}, {Name: "order", Type: "int"}, {Name: "type", Type: "string"}},
+				Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "user", ForeignKey: "from_id"}}},
+		}},
+	}
+	var verr *ValidationError
+	if err := Validate(req); !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	var paths []string
+	for _, e := range verr.Errors {
+		paths = append(paths, e.Path+": "+e.Message)
+	}
+	want := []string{
+		"/custom/models/1/fields/0/name: lesson.class is a Python keyword",
+		"/custom/models/1/fields/1/name: lesson.order is a reserved word in SQL",
+		"/custom/models/1/relations/0/foreign_key: lesson.from_id names the association from, which is a Python keyword",
+	}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("expected %q, got %q", want, paths)
+	}
+
+	req.Language, req.Framework, req.Database = "go", "gin", "mongodb"
+	if err := Validate(req); err != nil {
+		t.Fatalf("go on mongodb takes these names, got %v", err)
+	}
+}
+
+func TestValidateRejectsDuplicateModelAndFieldNames(t *testing.T) {
+	t.Parallel()
+
+	req := GenerateRequest{
+		Language:     "go",
+		Framework:    "gin",
+		Architecture: "clean",
+		Database:     "postgresql",
+		Root:         RootOptions{Mode: "new", Name: "ok"},
+		Custom: CustomOptions{Models: []DataModel{
+			{Name: "User", Fields: []DataField{{Name: "email", Type: "string"}, {Name: "Email", Type: "string"}}},
+			{Name: "User", Fields: []DataField{{Name: "name", Type: "string"}}},
+			{Name: "user", Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "post"}}},
+			{Name: "post", Relations: []DataRelation{{Kind: RelationBelongsTo, Model: "User"}}},
+		}},
+	}
+	var verr *ValidationError
+	if err := Validate(req); !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	for _, want := range []string{"/custom/models/0/fields/1/name", "/custom/models/1/name", "/custom/models/2/name"} {
+		if !slices.ContainsFunc(verr.Errors, func(e FieldError) bool { return e.Path == want && e.Code == CodeDuplicate }) {
+			t.Fatalf("expected a duplicate error at %s, got %+v", want, verr.Errors)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WriteTree materializes a FileTree under dir, creating directories and
+// .gitkeep placeholders the same way the generated scripts do. Existing files
+// are handled according to policy (see RootOptions.ConflictPolicy).
+func WriteTree(dir string, tree FileTree, policy string) error {
+	tree = cloneTree(tree)
+	ensureGitKeepFiles(&tree)
+
+	if policy == ConflictFail {
+		var existing []string
+		for _, f := range conflictCandidates(tree) {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f))); err == nil {
+				existing = append(existing, f)
+			}
+		}
+		if len(existing) > 0 {
+			return fmt.Errorf("refusing to overwrite existing files (root.on_conflict=fail): %s", strings.Join(existing, ", "))
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, d := range dirsSorted(tree.Dirs) {
+		if d == "." || d == "" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(dir, filepath.FromSlash(d)), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", d, err)
+		}
+	}
+	for _, f := range fileNamesSorted(tree.Files) {
+		full := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", f, err)
+		}
+		if _, err := os.Stat(full); err == nil && policy != ConflictOverwrite {
+			if policy == ConflictSkip || path.Base(f) == ".gitkeep" {
+				continue
+			}
+			if policy == ConflictBackup {
+				if err := copyFile(full, full+".bak"); err != nil {
+					return fmt.Errorf("failed to back up %s: %w", f, err)
+				}
+			}
+		}
+		if err := os.WriteFile(full, []byte(tree.Files[f]), archiveFileMode(f)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f, err)
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,8 @@
+// Package templates embeds the language and architecture templates so the
+// generator binaries are self-contained.
+package templates
+
+import "embed"
+
+//go:embed go node python
+var FS embed.FS
@@ -5,8 +5,16 @@ import { useEffect, useMemo, useState } from 'react';
 type Service = { name: string; port: number };
 type ToggleItem = { key: string; label: string };
 type CustomFileEntry = { path: string; content: string };
-type SchemaField = { name: string; type: string };
-type SchemaModel = { name: string; fields: SchemaField[] };
+type SchemaField = {
+  name: string;
+  type: string;
+  required?: boolean;
+  unique?: boolean;
+  index?: boolean;
+  max_length?: number;
+};
+type SchemaRelation = { kind: string; model: string };
+type SchemaModel = { name: string; fields: SchemaField[]; relations?: SchemaRelation[] };
 type SavedPreset = { name: string; config: Record<string, unknown> };
 type DecisionEntry = { code: string; category: string; message: string };
 type ScriptKind = 'bash' | 'powershell';
@@ -68,6 +76,7 @@ export default function Page() {
   const [architecture, setArchitecture] = useState('mvp');
   const [db, setDb] = useState('postgresql');
   const [useORM, setUseORM] = useState(true);
+  const [migrationTool, setMigrationTool] = useState('');
   const [serviceCommunication, setServiceCommunication] = useState('none');
   const [services, setServices] = useState<Service[]>(DEFAULT_SERVICES);
   const [infra, setInfra] = useState(DEFAULT_INFRA);
@@ -80,6 +89,8 @@ export default function Page() {
   const [moduleName, setModuleName] = useState('github.com/example/my-stacksprint-app');
   const [customFolders, setCustomFolders] = useState('');
   const [schemaModels, setSchemaModels] = useState<SchemaModel[]>(DEFAULT_MODELS);
+  const [schemaDDL, setSchemaDDL] = useState('');
+  const [schemaOpenAPI, setSchemaOpenAPI] = useState('');
   const [customFileEntries, setCustomFileEntries] = useState<CustomFileEntry[]>([{ path: '', content: '' }]);
   const [removeFolders, setRemoveFolders] = useState('');
   const [removeFiles, setRemoveFiles] = useState('');
@@ -103,6 +114,13 @@ export default function Page() {
     return ['fastapi', 'django'];
   }, [language]);
 
+  const migrationToolChoices = useMemo(() => {
+    if (db === 'none' || db === 'mongodb') return [];
+    if (language === 'go') return ['golang-migrate', 'goose'];
+    if (language === 'node') return useORM ? ['prisma', 'knex'] : ['knex'];
+    return framework === 'django' ? ['django'] : ['alembic'];
+  }, [language, framework, db, useORM]);
+
   function parseCsv(v: string): string[] {
     return v.split(',').map((s) => s.trim()).filter(Boolean);
   }
@@ -125,6 +143,7 @@ export default function Page() {
     services: architecture === 'microservices' ? services : [],
     db,
     use_orm: useORM,
+    migration_tool: migrationToolChoices.includes(migrationTool) ? migrationTool : '',
     service_communication: serviceCommunication,
     infra,
     features,
@@ -135,8 +154,11 @@ export default function Page() {
         .filter((model) => model.name.trim() !== '')
         .map((model) => ({
           name: model.name.trim(),
-          fields: model.fields.filter((field) => field.name.trim() !== '')
+          fields: model.fields.filter((field) => field.name.trim() !== ''),
+          relations: (model.relations ?? []).filter((rel) => rel.model.trim() !== '')
         })),
+      ddl: schemaDDL,
+      openapi: schemaOpenAPI,
       add_files: customFileEntries
         .filter((item) => item.path.trim() !== '')
         .map((item) => ({ path: item.path.trim(), content: item.content })),
@@ -158,12 +180,16 @@ export default function Page() {
     services,
     db,
     useORM,
+    migrationTool,
+    migrationToolChoices,
     serviceCommunication,
     infra,
     features,
     fileToggles,
     customFolders,
     schemaModels,
+    schemaDDL,
+    schemaOpenAPI,
     customFileEntries,
     removeFolders,
     removeFiles,
@@ -315,6 +341,35 @@ export default function Page() {
     );
   }
 
+  function addRelation(modelIndex: number) {
+    setSchemaModels((prev) =>
+      prev.map((model, i) => (
+        i === modelIndex ? { ...model, relations: [...(model.relations ?? []), { kind: 'belongs_to', model: '' }] } : model
+      ))
+    );
+  }
+
+  function removeRelation(modelIndex: number, relationIndex: number) {
+    setSchemaModels((prev) =>
+      prev.map((model, i) => (
+        i === modelIndex ? { ...model, relations: (model.relations ?? []).filter((_, idx) => idx !== relationIndex) } : model
+      ))
+    );
+  }
+
+  function updateRelation(modelIndex: number, relationIndex: number, patch: Partial<SchemaRelation>) {
+    setSchemaModels((prev) =>
+      prev.map((model, i) => (
+        i === modelIndex
+          ? {
+            ...model,
+            relations: (model.relations ?? []).map((rel, idx) => (idx === relationIndex ? { ...rel, ...patch } : rel))
+          }
+          : model
+      ))
+    );
+  }
+
   function updateField(modelIndex: number, fieldIndex: number, patch: Partial<SchemaField>) {
     setSchemaModels((prev) =>
       prev.map((model, i) => (
@@ -358,6 +413,7 @@ export default function Page() {
     setArchitecture((config.architecture as string) || 'mvp');
     setDb((config.db as string) || 'postgresql');
     setUseORM(Boolean(config.use_orm));
+    setMigrationTool((config.migration_tool as string) || '');
     setServiceCommunication((config.service_communication as string) || 'none');
 
     const cfgServices = (config.services as Service[]) || [];
@@ -371,6 +427,8 @@ export default function Page() {
     setCustomFolders(Array.isArray(custom.add_folders) ? (custom.add_folders as string[]).join(', ') : '');
     const models = Array.isArray(custom.models) ? (custom.models as SchemaModel[]) : [];
     setSchemaModels(models.length > 0 ? models : DEFAULT_MODELS);
+    setSchemaDDL(typeof custom.ddl === 'string' ? custom.ddl : '');
+    setSchemaOpenAPI(typeof custom.openapi === 'string' ? custom.openapi : '');
     const addFiles = Array.isArray(custom.add_files) ? (custom.add_files as CustomFileEntry[]) : [];
     setCustomFileEntries(addFiles.length > 0 ? addFiles : [{ path: '', content: '' }]);
     setRemoveFolders(Array.isArray(custom.remove_folders) ? (custom.remove_folders as string[]).join(', ') : '');
@@ -609,6 +667,7 @@ export default function Page() {
               <select value={db} onChange={(e) => setDb(e.target.value)}>
                 <option value="postgresql">PostgreSQL</option>
                 <option value="mysql">MySQL</option>
+                <option value="sqlite">SQLite</option>
                 <option value="mongodb">MongoDB</option>
                 <option value="none">None</option>
               </select>
@@ -617,6 +676,17 @@ export default function Page() {
               <input type="checkbox" checked={useORM} onChange={(e) => setUseORM(e.target.checked)} />
               <span>Use ORM (GORM / Prisma / SQLAlchemy)</span>
             </label>
+            {migrationToolChoices.length > 0 && (
+              <div className="field">
+                <label>Migration Tool</label>
+                <select value={migrationToolChoices.includes(migrationTool) ? migrationTool : ''} onChange={(e) => setMigrationTool(e.target.value)}>
+                  <option value="">Plain SQL file</option>
+                  {migrationToolChoices.map((tool) => (
+                    <option key={tool} value={tool}>{tool}</option>
+                  ))}
+                </select>
+              </div>
+            )}
             <div className="schema-builder">
               <label>Schema Builder</label>
               {schemaModels.map((model, modelIndex) => (
@@ -647,12 +717,72 @@ export default function Page() {
                         <option value="datetime">datetime</option>
                       </select>
                       <button type="button" className="ghost" onClick={() => removeField(modelIndex, fieldIndex)}>Remove Field</button>
+                      <div className="schema-constraints">
+                        {(['required', 'unique', 'index'] as const).map((flag) => (
+                          <label className="toggle" key={flag}>
+                            <input
+                              type="checkbox"
+                              checked={Boolean(field[flag])}
+                              onChange={(e) => updateField(modelIndex, fieldIndex, { [flag]: e.target.checked || undefined } as Partial<SchemaField>)}
+                            />
+                            <span>{flag}</span>
+                          </label>
+                        ))}
+                        {field.type === 'string' && (
+                          <input
+                            type="number"
+                            min={1}
+                            value={field.max_length ?? ''}
+                            onChange={(e) => updateField(modelIndex, fieldIndex, { max_length: Number(e.target.value) || undefined })}
+                            placeholder="max length"
+                          />
+                        )}
+                      </div>
                     </div>
                   ))}
                   <button type="button" className="ghost" onClick={() => addField(modelIndex)}>Add Field</button>
+                  {(model.relations ?? []).map((rel, relationIndex) => (
+                    <div className="row schema-field" key={`relation-${modelIndex}-${relationIndex}`}>
+                      <select
+                        value={rel.kind}
+                        onChange={(e) => updateRelation(modelIndex, relationIndex, { kind: e.target.value })}
+                      >
+                        <option value="belongs_to">belongs_to</option>
+                        <option value="has_many">has_many</option>
+                        <option value="many_to_many">many_to_many</option>
+                      </select>
+                      <select
+                        value={rel.model}
+                        onChange={(e) => updateRelation(modelIndex, relationIndex, { model: e.target.value })}
+                      >
+                        <option value="">model</option>
+                        {schemaModels
+                          .filter((other, idx) => idx !== modelIndex && other.name.trim() !== '')
+                          .map((other) => (
+                            <option key={other.name} value={other.name}>{other.name}</option>
+                          ))}
+                      </select>
+                      <button type="button" className="ghost" onClick={() => removeRelation(modelIndex, relationIndex)}>Remove Relation</button>
+                    </div>
+                  ))}
+                  <button type="button" className="ghost" onClick={() => addRelation(modelIndex)}>Add Relation</button>
                 </div>
               ))}
               <button type="button" className="ghost" onClick={addModel}>Add Model</button>
+              <label>Import CREATE TABLE DDL (Postgres or MySQL)</label>
+              <textarea
+                rows={6}
+                value={schemaDDL}
+                onChange={(e) => setSchemaDDL(e.target.value)}
+                placeholder="CREATE TABLE users (id serial PRIMARY KEY, email varchar(120) NOT NULL UNIQUE);"
+              />
+              <label>Import OpenAPI 3 document (YAML or JSON)</label>
+              <textarea
+                rows={6}
+                value={schemaOpenAPI}
+                onChange={(e) => setSchemaOpenAPI(e.target.value)}
+                placeholder="openapi: 3.0.3"
+              />
             </div>
             <div className="toggle-grid">
               {infraKeys.map((item) => (